@@ -0,0 +1,205 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// decodeBatch reads the generate-signature requests of a batch from given reader, either as a JSON array or as a
+// stream of JSON values such as newline-delimited JSON. In strict mode, requests containing unknown fields and input
+// exceeding the maximum request size are rejected.
+func (c *CLI) decodeBatch(r io.Reader, batch *plugin.GenerateSignatureBatchRequest) error {
+	if c.strict {
+		var err error
+		if r, err = c.limitRequest(r, batch); err != nil {
+			return err
+		}
+	}
+
+	var values []json.RawMessage
+	dec := json.NewDecoder(r)
+	for {
+		var v json.RawMessage
+		if err := dec.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			c.logger.Errorf("%T unmarshalling error: %v", batch, err)
+			return plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 1 && bytes.HasPrefix(values[0], []byte("[")) {
+		if err := json.Unmarshal(values[0], &values); err != nil {
+			c.logger.Errorf("%T unmarshalling error: %v", batch, err)
+			return plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
+		}
+	}
+
+	*batch = make(plugin.GenerateSignatureBatchRequest, len(values))
+	for i, v := range values {
+		dec := json.NewDecoder(bytes.NewReader(v))
+		if c.strict {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&(*batch)[i]); err != nil {
+			c.logger.Errorf("%T unmarshalling error for request %d: %v", batch, i, err)
+			return plugin.NewValidationErrorf("%s: request %d is invalid", plugin.ErrorMsgMalformedInput, i)
+		}
+	}
+	return nil
+}
+
+// generateSignatureBatch generates the signatures of given batch, either at once if the plugin implements
+// plugin.BatchSigner or by calling GenerateSignature for each request with bounded concurrency.
+// Invalid requests and failed signatures are reported in the result of their request, along with responses
+// not matching their request.
+func (c *CLI) generateSignatureBatch(ctx context.Context, p plugin.SignatureGeneratorPlugin, batch plugin.GenerateSignatureBatchRequest) plugin.GenerateSignatureBatchResponse {
+	results := make(plugin.GenerateSignatureBatchResponse, len(batch))
+	var pending []int
+	for i, req := range batch {
+		if err := c.validateBatchRequest(req); err != nil {
+			c.logger.Errorf("batch request %d validation error: %v", i, err)
+			results[i].Error = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return results
+	}
+
+	if bs, ok := p.(plugin.BatchSigner); ok {
+		c.signBatch(ctx, bs, batch, pending, results)
+	} else {
+		c.signEach(ctx, p, batch, pending, results)
+	}
+	return results
+}
+
+// validateBatchRequest validates a single request of a batch and checks that its contract version is supported
+// by the plugin.
+func (c *CLI) validateBatchRequest(req *plugin.GenerateSignatureRequest) *plugin.Error {
+	if req == nil {
+		return plugin.NewValidationErrorf("%s: request cannot be empty", plugin.ErrorMsgMalformedInput)
+	}
+	if err := req.Validate(); err != nil {
+		return malformedInputError(err)
+	}
+	if err := validateContractVersion(c.md, req); err != nil {
+		return toPluginError(err)
+	}
+	return nil
+}
+
+// signBatch generates the signatures of the pending requests of given batch at once using given plugin.BatchSigner
+// and stores them in results.
+func (c *CLI) signBatch(ctx context.Context, bs plugin.BatchSigner, batch plugin.GenerateSignatureBatchRequest, pending []int, results plugin.GenerateSignatureBatchResponse) {
+	req := make(plugin.GenerateSignatureBatchRequest, len(pending))
+	for j, i := range pending {
+		req[j] = batch[i]
+	}
+
+	c.logger.Debugf("generating %d signatures using batch signer", len(req))
+	resp, err := waitOrAbandon(ctx, c, "batch signer", func() (plugin.GenerateSignatureBatchResponse, error) {
+		return c.callBatchSigner(ctx, bs, req)
+	})
+	if err == nil && len(resp) != len(req) {
+		err = plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, fmt.Sprintf("batch response contains %d results but %d were requested", len(resp), len(req)))
+	}
+	for j, i := range pending {
+		switch {
+		case err != nil:
+			results[i] = c.batchResult(i, req[j], nil, err)
+		case resp[j].Error != nil:
+			results[i] = c.batchResult(i, req[j], nil, resp[j].Error)
+		default:
+			results[i] = c.batchResult(i, req[j], resp[j].Response, nil)
+		}
+	}
+}
+
+// signEach generates the signatures of the pending requests of given batch one by one, with up to the configured
+// concurrency, and stores them in results. Requests not completed before ctx is done fail with a timeout error,
+// while the results of the completed ones are kept.
+func (c *CLI) signEach(ctx context.Context, p plugin.SignatureGeneratorPlugin, batch plugin.GenerateSignatureBatchRequest, pending []int, results plugin.GenerateSignatureBatchResponse) {
+	c.logger.Debugf("generating %d signatures with concurrency %d", len(pending), c.concurrency)
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for _, i := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = plugin.GenerateSignatureBatchResult{Error: c.contextError(ctx)}
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resp, err := waitOrAbandon(ctx, c, fmt.Sprintf("batch request %d", i), func() (*plugin.GenerateSignatureResponse, error) {
+				return c.callGenerateSignature(ctx, p, batch[i])
+			})
+			results[i] = c.batchResult(i, batch[i], resp, err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// batchResult returns the result of the i-th request of a batch given the response or error of the plugin,
+// validating the response against the request.
+func (c *CLI) batchResult(i int, req *plugin.GenerateSignatureRequest, resp *plugin.GenerateSignatureResponse, err error) plugin.GenerateSignatureBatchResult {
+	if err != nil {
+		c.logger.Errorf("batch request %d error: %v", i, err)
+		return plugin.GenerateSignatureBatchResult{Error: toPluginError(err)}
+	}
+	if err := resp.Validate(req); err != nil {
+		c.logger.Errorf("batch request %d response validation error: %v", i, err)
+		var plError *plugin.Error
+		if errors.As(err, &plError) {
+			return plugin.GenerateSignatureBatchResult{Error: plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, plError.Message)}
+		}
+		return plugin.GenerateSignatureBatchResult{Error: plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, err.Error())}
+	}
+	return plugin.GenerateSignatureBatchResult{Response: resp}
+}
+
+// callGenerateSignature calls GenerateSignature of given plugin, converting any panic into an error.
+func (c *CLI) callGenerateSignature(ctx context.Context, p plugin.SignatureGeneratorPlugin, req *plugin.GenerateSignatureRequest) (resp *plugin.GenerateSignatureResponse, err error) {
+	defer c.recoverPanic(&err)
+	return p.GenerateSignature(ctx, req)
+}
+
+// callBatchSigner calls GenerateSignatureBatch of given plugin.BatchSigner, converting any panic into an error.
+func (c *CLI) callBatchSigner(ctx context.Context, bs plugin.BatchSigner, req plugin.GenerateSignatureBatchRequest) (resp plugin.GenerateSignatureBatchResponse, err error) {
+	defer c.recoverPanic(&err)
+	return bs.GenerateSignatureBatch(ctx, req)
+}
+
+// toPluginError converts given error into a plugin error, which is a generic one unless err already is.
+func toPluginError(err error) *plugin.Error {
+	var plError *plugin.Error
+	if errors.As(err, &plError) {
+		return plError
+	}
+	return plugin.NewGenericError(err.Error())
+}
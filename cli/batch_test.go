@@ -0,0 +1,289 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const (
+	batchSignReq      = "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\"}"
+	batchSignResp     = "{\"response\":{\"keyId\":\"someKeyId\",\"signature\":\"YWJjZA==\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]}}"
+	batchInvalidReq   = "{\"contractVersion\":\"1.0\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\"}"
+	batchInvalidResp  = "{\"error\":{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: keyId cannot be empty\"}}"
+	batchContractReq  = "{\"contractVersion\":\"2.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\"}"
+	batchContractResp = "{\"error\":{\"errorCode\":\"UNSUPPORTED_CONTRACT_VERSION\",\"errorMessage\":\"\\\"2.0\\\" is not a supported notary plugin contract version\"}}"
+)
+
+func TestRunSignatureBatch(t *testing.T) {
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(2))
+	panicCli, _ := New(&panicPlugin{Plugin: mock.NewSigGeneratorPlugin(false)}, WithSignatureBatch(2))
+	strictCli, _ := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(2), WithStrictDecoding(0))
+	tests := map[string]struct {
+		c        *CLI
+		in       string
+		expected string
+	}{
+		"array": {
+			c:        sigGenCli,
+			in:       "[" + batchSignReq + "," + batchInvalidReq + "," + batchContractReq + ",null]",
+			expected: "[" + batchSignResp + "," + batchInvalidResp + "," + batchContractResp + ",{\"error\":{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: request cannot be empty\"}}]",
+		},
+		"ndjson": {
+			c:        sigGenCli,
+			in:       batchSignReq + "\n" + batchInvalidReq + "\n" + batchContractReq + "\n" + batchSignReq + "\n",
+			expected: "[" + batchSignResp + "," + batchInvalidResp + "," + batchContractResp + "," + batchSignResp + "]",
+		},
+		"singleRequest": {
+			c:        strictCli,
+			in:       batchSignReq,
+			expected: "[" + batchSignResp + "]",
+		},
+		"pluginPanic": {
+			c:        panicCli,
+			in:       "[" + batchSignReq + "," + batchInvalidReq + "]",
+			expected: "[{\"error\":{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin panicked: GenerateSignature() panicked\"}}," + batchInvalidResp + "]",
+		},
+		"batchSigner": {
+			c: newBatchSignerCli(t, func(req plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+				return plugin.GenerateSignatureBatchResponse{
+					{Response: &plugin.GenerateSignatureResponse{KeyID: "someKeyId", Signature: []byte("abcd"), SigningAlgorithm: plugin.SignatureAlgorithmRSASSA_PSS_SHA256, CertificateChain: [][]byte{[]byte("abcd"), []byte("wxyz")}}},
+					{Error: plugin.NewError(plugin.ErrorCodeThrottled, "slow down")},
+					{Response: &plugin.GenerateSignatureResponse{KeyID: "otherKeyId"}},
+				}, nil
+			}),
+			in:       "[" + batchSignReq + "," + batchInvalidReq + "," + batchSignReq + "," + batchSignReq + "]",
+			expected: "[" + batchSignResp + "," + batchInvalidResp + ",{\"error\":{\"errorCode\":\"THROTTLED\",\"errorMessage\":\"slow down\"}},{\"error\":{\"errorCode\":\"ERROR\",\"errorMessage\":\"Failed to generate response. Error: keyId \\\"otherKeyId\\\" doesn't match the requested keyId \\\"someKeyId\\\"\"}}]",
+		},
+		"batchSignerError": {
+			c: newBatchSignerCli(t, func(req plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+				return nil, plugin.NewError(plugin.ErrorCodeAccessDenied, "denied")
+			}),
+			in:       "[" + batchSignReq + "," + batchSignReq + "]",
+			expected: "[{\"error\":{\"errorCode\":\"ACCESS_DENIED\",\"errorMessage\":\"denied\"}},{\"error\":{\"errorCode\":\"ACCESS_DENIED\",\"errorMessage\":\"denied\"}}]",
+		},
+		"batchSignerLength": {
+			c: newBatchSignerCli(t, func(req plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+				return plugin.GenerateSignatureBatchResponse{}, nil
+			}),
+			in:       "[" + batchSignReq + "]",
+			expected: "[{\"error\":{\"errorCode\":\"ERROR\",\"errorMessage\":\"Failed to generate response. Error: batch response contains 0 results but 1 were requested\"}}]",
+		},
+		"batchSignerPanic": {
+			c: newBatchSignerCli(t, func(req plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+				panic("batch panic")
+			}),
+			in:       "[" + batchSignReq + "]",
+			expected: "[{\"error\":{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin panicked: batch panic\"}}]",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := []string{pluginExecutable, string(plugin.CommandGenerateSignatureBatch)}
+			if code := test.c.Run(context.Background(), args, strings.NewReader(test.in), &stdout, &stderr); code != 0 {
+				t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+			}
+			if stdout.String() != test.expected {
+				t.Errorf("Run() expected stdout '%s' but got '%s'", test.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunSignatureBatchError(t *testing.T) {
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(1))
+	strictCli, _ := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(1), WithStrictDecoding(200))
+	tests := map[string]struct {
+		c      *CLI
+		in     string
+		stderr string
+	}{
+		"notEnabled": {
+			c:      cli,
+			in:     "[" + batchSignReq + "]",
+			stderr: "Invalid command, valid commands are: <generate-envelope|get-plugin-metadata|verify-signature|version>",
+		},
+		"empty": {
+			c:      sigGenCli,
+			in:     "[]",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: batch cannot be empty\"}",
+		},
+		"malformed": {
+			c:      sigGenCli,
+			in:     "[" + batchSignReq,
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON\"}",
+		},
+		"invalidRequest": {
+			c:      sigGenCli,
+			in:     "[" + batchSignReq + ",1]",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: request 1 is invalid\"}",
+		},
+		"unknownField": {
+			c:      strictCli,
+			in:     "{\"contractVersion\":\"1.0\",\"unknown\":1}",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: request 0 is invalid\"}",
+		},
+		"tooLarge": {
+			c:      strictCli,
+			in:     "[" + batchSignReq + "," + batchSignReq + "]",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: request exceeds maximum size of 200 bytes\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := []string{pluginExecutable, string(plugin.CommandGenerateSignatureBatch)}
+			if code := test.c.Run(context.Background(), args, strings.NewReader(test.in), &stdout, &stderr); code == 0 {
+				t.Fatalf("Run() expected nonzero exit code, stdout: %s", stdout.String())
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestSignatureBatchConcurrency(t *testing.T) {
+	pl := &concurrencyPlugin{Plugin: mock.NewSigGeneratorPlugin(false), unblock: make(chan struct{})}
+	c, err := New(pl, WithSignatureBatch(2))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandGenerateSignatureBatch)}
+	in := strings.Repeat(batchSignReq+"\n", 5)
+	done := make(chan int)
+	go func() {
+		done <- c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr)
+	}()
+	for deadline := time.Now().Add(5 * time.Second); pl.running() < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if running := pl.running(); running != 2 {
+		t.Errorf("expected 2 concurrent signatures but got %d", running)
+	}
+	close(pl.unblock)
+	if code := <-done; code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	if pl.max > 2 {
+		t.Errorf("expected at most 2 concurrent signatures but got %d", pl.max)
+	}
+	if expected := "[" + strings.TrimSuffix(strings.Repeat(batchSignResp+",", 5), ",") + "]"; stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestRunSignatureBatchTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	pl := &slowPayloadPlugin{Plugin: mock.NewSigGeneratorPlugin(false), unblock: unblock}
+	c, err := New(pl, WithSignatureBatch(2), WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	slowReq := strings.Replace(batchSignReq, "em9w", "c2xvdw==", 1)
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandGenerateSignatureBatch)}
+	if code := c.Run(context.Background(), args, strings.NewReader("["+batchSignReq+","+slowReq+"]"), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	expected := "[" + batchSignResp + ",{\"error\":{\"errorCode\":\"TIMEOUT\",\"errorMessage\":\"plugin command timed out\"}}]"
+	if stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestNewSignatureBatchWithoutCapability(t *testing.T) {
+	_, err := New(mock.NewPlugin(false), WithSignatureBatch(1))
+	expected := "{\"errorCode\":\"ERROR\",\"errorMessage\":\"generate-signature-batch command requires capability \\\"SIGNATURE_GENERATOR.RAW\\\"\"}"
+	if err == nil || err.Error() != expected {
+		t.Errorf("New() expected error '%s' but found '%v'", expected, err)
+	}
+}
+
+func newBatchSignerCli(t *testing.T, f func(plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error)) *CLI {
+	t.Helper()
+	c, err := New(&batchSignerPlugin{Plugin: mock.NewSigGeneratorPlugin(false), f: f}, WithSignatureBatch(1))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	return c
+}
+
+// batchSignerPlugin wraps a plugin and implements plugin.BatchSigner using f.
+type batchSignerPlugin struct {
+	plugin.Plugin
+	f func(plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error)
+}
+
+func (p *batchSignerPlugin) GenerateSignatureBatch(_ context.Context, req plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+	return p.f(req)
+}
+
+// concurrencyPlugin wraps a plugin and records the maximum number of concurrent GenerateSignature calls, which are
+// blocked until unblock is closed.
+type concurrencyPlugin struct {
+	plugin.Plugin
+	unblock chan struct{}
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (p *concurrencyPlugin) running() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+func (p *concurrencyPlugin) GenerateSignature(ctx context.Context, req *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	p.mu.Lock()
+	p.current++
+	if p.current > p.max {
+		p.max = p.current
+	}
+	p.mu.Unlock()
+	<-p.unblock
+	defer func() {
+		p.mu.Lock()
+		p.current--
+		p.mu.Unlock()
+	}()
+	return p.Plugin.GenerateSignature(ctx, req)
+}
+
+// slowPayloadPlugin wraps a plugin and blocks GenerateSignature for the "slow" payload until unblock is closed,
+// ignoring the context.
+type slowPayloadPlugin struct {
+	plugin.Plugin
+	unblock chan struct{}
+}
+
+func (p *slowPayloadPlugin) GenerateSignature(ctx context.Context, req *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	if string(req.Payload) == "slow" {
+		<-p.unblock
+	}
+	return p.Plugin.GenerateSignature(ctx, req)
+}
@@ -20,12 +20,20 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
 	"github.com/notaryproject/notation-plugin-framework-go/log"
@@ -34,131 +42,523 @@ import (
 
 // CLI struct is used to create an executable for plugin.
 type CLI struct {
-	pl     plugin.Plugin
-	logger log.Logger
+	pl             plugin.GenericPlugin
+	md             *plugin.GetMetadataResponse
+	mdMu           sync.Mutex
+	logger         log.Logger
+	timeout        time.Duration
+	interceptors   []Interceptor
+	strict         bool
+	maxRequestSize int64
+	transcript     string
+	redactions     []string
+	fixtureDir     string
+	socket         string
+	exitCodes      map[plugin.ErrorCode]int
+	commands       []command
+	batch          bool
+	concurrency    int
+	doctor         bool
+	abandoned      atomic.Int32
 }
 
-// New creates a new CLI using given plugin
-func New(pl plugin.Plugin) (*CLI, error) {
-	return NewWithLogger(pl, &discardLogger{})
+// maxAbandonedCalls is the maximum number of plugin calls still running after the CLI stopped waiting for them,
+// beyond which new commands are throttled. It only matters to long-running CLIs, see Serve and HTTPHandler.
+const maxAbandonedCalls = 64
+
+// New creates a new CLI using given plugin and options.
+// The plugin metadata is fetched, validated and cached at construction time, and New fails with a plugin.Error if
+// the metadata can't be fetched or is invalid. The plugin must implement the interfaces required by the
+// capabilities declared in its metadata, i.e. plugin.SignatureGeneratorPlugin for plugin.CapabilitySignatureGenerator,
+// plugin.EnvelopeGeneratorPlugin for plugin.CapabilityEnvelopeGenerator and plugin.VerifyPlugin for any verifier
+// capability. If WithDoctor is used, New doesn't fail on a metadata error so that the doctor command can report it,
+// and the other commands return the error instead.
+func New(pl plugin.GenericPlugin, opts ...Option) (*CLI, error) {
+	return NewWithLogger(pl, &discardLogger{}, opts...)
 }
 
-// NewWithLogger creates a new CLI using given plugin and logger
-func NewWithLogger(pl plugin.Plugin, l log.Logger) (*CLI, error) {
+// NewWithLogger creates a new CLI using given plugin, logger and options.
+// See New for the interfaces the plugin is required to implement.
+func NewWithLogger(pl plugin.GenericPlugin, l log.Logger, opts ...Option) (*CLI, error) {
 	if pl == nil {
 		return nil, errors.New("plugin cannot be nil")
 	}
 
-	return &CLI{
-		pl:     pl,
-		logger: l,
-	}, nil
+	c := &CLI{
+		pl:         pl,
+		logger:     l,
+		redactions: defaultRedactions,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := validateExitCodes(c.exitCodes); err != nil {
+		return nil, err
+	}
+	if err := validateCommands(c.commands); err != nil {
+		return nil, err
+	}
+	if err := c.loadMetadata(context.Background()); err != nil && !c.doctor {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// loadMetadata fetches and validates the plugin metadata within the configured timeout and caches it, see
+// fetchMetadata. It is called by New, and by the commands of a CLI whose construction tolerated a metadata error.
+// Only a successful load is cached, so that a transient error doesn't break a long-running CLI such as HTTPHandler
+// or Serve: the next call tries again.
+func (c *CLI) loadMetadata(ctx context.Context) *plugin.Error {
+	c.mdMu.Lock()
+	defer c.mdMu.Unlock()
+	if c.md != nil {
+		return nil
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	md, err := c.fetchMetadata(ctx, nil)
+	if err != nil {
+		c.logger.Errorf("failed to load plugin metadata: %v", err)
+		return toPluginError(err)
+	}
+	c.md = md
+	return nil
+}
+
+// fetchMetadata fetches the plugin metadata using given plugin config and validates it. It also checks that the
+// plugin implements the interfaces required by the declared capabilities and by the enabled commands.
+func (c *CLI) fetchMetadata(ctx context.Context, pluginConfig map[string]string) (*plugin.GetMetadataResponse, error) {
+	md, err := c.getMetadata(ctx, pluginConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := md.Validate(); err != nil {
+		var plErr *plugin.Error
+		if errors.As(err, &plErr) {
+			return nil, plugin.NewGenericErrorf("invalid plugin metadata: %s", plErr.Message)
+		}
+		return nil, plugin.NewGenericErrorf("invalid plugin metadata: %v", err)
+	}
+	if err := validateImplementation(c.pl, md); err != nil {
+		return nil, plugin.NewGenericError(err.Error())
+	}
+	if c.batch && !md.HasCapability(plugin.CapabilitySignatureGenerator) {
+		return nil, plugin.NewGenericErrorf("%s command requires capability %q", plugin.CommandGenerateSignatureBatch, plugin.CapabilitySignatureGenerator)
+	}
+	return md, nil
 }
 
 // Execute is main controller that reads/validates commands, parses input, executes relevant plugin functions
 // and returns corresponding output.
+// It reads input from os.Stdin, writes output to os.Stdout and os.Stderr, and terminates the process with a
+// nonzero exit code in case of failure, see WithExitCodes. The context passed to the plugin is cancelled when the
+// process receives SIGINT or SIGTERM.
+// Any other output written to stdout or stderr while the plugin command runs is logged as a warning instead.
 func (c *CLI) Execute(ctx context.Context, args []string) {
-	c.validateArgs(ctx, args)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	stdout, stderr, restoreOutput := c.isolateOutput()
+	exitCode := c.Run(ctx, args, os.Stdin, stdout, stderr)
+	restoreOutput()
+	stop()
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
 
-	rescueStdOut := deferStdout()
-	command := args[1]
-	var resp any
-	var err error
-	switch plugin.Command(command) {
-	case plugin.CommandGetMetadata:
-		var request plugin.GetMetadataRequest
-		err = c.unmarshalRequest(&request)
-		if err == nil {
-			c.logger.Debugf("executing %s plugin's GetMetadata function", reflect.TypeOf(c.pl))
-			resp, err = c.pl.GetMetadata(ctx, &request)
+// Run reads/validates commands, parses input from stdin, executes relevant plugin functions and writes
+// corresponding output to stdout or error to stderr.
+// Running the executable without a command, or with help, --help or -h, prints its usage to stdout.
+// The hidden "serve --socket <path>" command runs the plugin as a daemon, see ListenAndServe, and the hidden
+// "completion <bash|fish|zsh>" command prints the shell completion script of the plugin executable.
+// Unlike Execute, Run doesn't depend on process globals, which makes it suitable for testing plugins in-process.
+// It returns the exit code the plugin executable is expected to terminate with.
+func (c *CLI) Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if c.transcript != "" || c.fixtureDir != "" {
+		return c.runRecorded(ctx, args, stdin, stdout, stderr)
+	}
+	return c.run(ctx, args, stdin, stdout, stderr)
+}
+
+// Call executes given plugin command with given request, i.e. the JSON the plugin executable reads from stdin, and
+// returns the JSON response the executable writes to stdout or the plugin.Error it writes to stderr. The request goes
+// through the same validation, interceptors and timeout as with the executable, so that other transports can serve
+// the plugin, see the grpcplugin package. Commands the plugin doesn't support fail with a generic error.
+func (c *CLI) Call(ctx context.Context, command plugin.Command, request []byte) ([]byte, *plugin.Error) {
+	if err := c.loadMetadata(ctx); err != nil {
+		return nil, err
+	}
+	if !c.isPluginCommand(string(command)) {
+		return nil, plugin.NewGenericErrorf("command %q not found", command)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	op, pluginErr := c.execRequest(ctx, command, bytes.NewReader(request))
+	if pluginErr != nil {
+		return nil, pluginErr
+	}
+	return []byte(op), nil
+}
+
+func (c *CLI) run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// the doctor command reports a metadata failure instead of failing, see New
+	isDoctor := c.doctor && len(args) > 1 && args[1] == doctorCommand
+	if err := c.loadMetadata(ctx); err != nil && !isDoctor {
+		return c.deliverPluginError(stderr, err)
+	}
+
+	if isHelpArgs(args) {
+		c.printHelp(stdout)
+		return 0
+	}
+
+	if args[1] == serveCommand {
+		if err := c.serve(ctx, args[2:]); err != nil {
+			return deliverError(stderr, err.Error())
+		}
+		return 0
+	}
+
+	if args[1] == completionCommand {
+		if err := c.printCompletion(args[2:], stdout); err != nil {
+			return deliverError(stderr, err.Error())
+		}
+		return 0
+	}
+
+	// the doctor command reports an invalid executable name instead of failing
+	if isDoctor {
+		passed, err := c.runDoctor(ctx, args[0], args[2:], stdout)
+		if err != nil {
+			return deliverError(stderr, err.Error())
+		}
+		if !passed {
+			return exitCodeGeneric
+		}
+		return 0
+	}
+
+	if err := c.validateArgs(args); err != nil {
+		return deliverError(stderr, err.Error())
+	}
+
+	command := plugin.Command(args[1])
+	if command == plugin.Version {
+		if err := printVersion(c.md, args[2:], stdout); err != nil {
+			return deliverError(stderr, err.Error())
+		}
+		return 0
+	}
+
+	op, pluginErr := c.handle(ctx, command, stdin)
+	if pluginErr != nil {
+		return c.deliverPluginError(stderr, pluginErr)
+	}
+	_, _ = fmt.Fprint(stdout, op)
+	return 0
+}
+
+// handle executes given plugin command within the configured timeout and returns the marshalled response.
+// If a daemon socket is set by WithSocket, the request is forwarded to the daemon, falling back to local execution
+// when the daemon isn't available, doesn't run as the current user or serves another plugin or version.
+func (c *CLI) handle(ctx context.Context, command plugin.Command, stdin io.Reader) (string, *plugin.Error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if c.socket != "" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			c.logger.Errorf("%s reading error: %v", command, err)
+			return "", plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
+		}
+		if op, pluginErr, ok := c.forward(ctx, c.socket, command, data); ok {
+			return op, pluginErr
+		}
+		stdin = bytes.NewReader(data)
+	}
+	return c.execRequest(ctx, command, stdin)
+}
+
+// withTimeout returns a copy of ctx which is cancelled once the configured timeout elapses, if any.
+func (c *CLI) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return ctx, func() {}
+}
+
+// execRequest executes given plugin command locally and returns the marshalled response.
+func (c *CLI) execRequest(ctx context.Context, command plugin.Command, stdin io.Reader) (string, *plugin.Error) {
+	resp, err := c.executeWithContext(ctx, command, stdin)
+	return c.marshalResponse(resp, err)
+}
+
+// executeWithContext executes given command and stops waiting for the plugin once ctx is done, so that a
+// well-formed timeout error is returned instead of the process being killed mid-write, see waitOrAbandon.
+// The generate-signature-batch command waits for each of its requests instead, so that the results of the
+// requests completed before ctx is done are kept.
+func (c *CLI) executeWithContext(ctx context.Context, command plugin.Command, stdin io.Reader) (any, error) {
+	if command == plugin.CommandGenerateSignatureBatch {
+		return c.execute(ctx, command, stdin)
+	}
+	return waitOrAbandon(ctx, c, string(command), func() (any, error) {
+		return c.execute(ctx, command, stdin)
+	})
+}
+
+// waitOrAbandon calls f and waits for it until ctx is done, returning a timeout error if it didn't return by then.
+// A plugin ignoring ctx keeps running in the background once abandoned, which is harmless for a one-shot process
+// but holds resources in a long-running CLI, so abandoned calls are logged and new calls are throttled while
+// maxAbandonedCalls of them are still running.
+func waitOrAbandon[T any](ctx context.Context, c *CLI, name string, f func() (T, error)) (T, error) {
+	var zero T
+	if ctx.Done() == nil {
+		return f()
+	}
+	if n := c.abandoned.Load(); n >= maxAbandonedCalls {
+		c.logger.Errorf("%s throttled: %d abandoned plugin calls are still running", name, n)
+		return zero, plugin.NewError(plugin.ErrorCodeThrottled, "too many plugin calls are still running after being interrupted")
+	}
+
+	type result struct {
+		resp T
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := f()
+		done <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() != nil {
+			return zero, c.contextError(ctx)
 		}
+		return r.resp, r.err
+	case <-ctx.Done():
+		n := c.abandoned.Add(1)
+		c.logger.Warnf("abandoning %s, the plugin didn't return once interrupted (%d abandoned calls running)", name, n)
+		go func() {
+			<-done
+			c.abandoned.Add(-1)
+			c.logger.Warnf("abandoned %s returned", name)
+		}()
+		return zero, c.contextError(ctx)
+	}
+}
+
+// contextError converts the error of a done context into a timeout plugin error.
+func (c *CLI) contextError(ctx context.Context) *plugin.Error {
+	c.logger.Errorf("plugin command interrupted: %v", ctx.Err())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return plugin.NewError(plugin.ErrorCodeTimeout, "plugin command timed out")
+	}
+	return plugin.NewError(plugin.ErrorCodeTimeout, "plugin command was cancelled")
+}
+
+// execute reads the request of given command from stdin, checks that its contract version and requested
+// capabilities are supported by the plugin, executes relevant plugin function through the configured
+// interceptors and validates the response against the request the plugin function received.
+// Any panic raised by the plugin or interceptors is recovered and returned as a generic plugin error.
+func (c *CLI) execute(ctx context.Context, command plugin.Command, stdin io.Reader) (resp any, err error) {
+	defer c.recoverPanic(&err)
+
+	var request plugin.Request
+	var handler Handler
+	switch command {
+	case plugin.CommandGetMetadata:
+		request, handler = &plugin.GetMetadataRequest{}, handlerFor(c.pl.GetMetadata)
 	case plugin.CommandGenerateEnvelope:
-		var request plugin.GenerateEnvelopeRequest
-		err = c.unmarshalRequest(&request)
-		if err == nil {
-			c.logger.Debugf("executing %s plugin's GenerateEnvelope function", reflect.TypeOf(c.pl))
-			resp, err = c.pl.GenerateEnvelope(ctx, &request)
+		if p, ok := c.pl.(plugin.EnvelopeGeneratorPlugin); ok {
+			request, handler = &plugin.GenerateEnvelopeRequest{}, handlerFor(p.GenerateEnvelope)
 		}
 	case plugin.CommandVerifySignature:
-		var request plugin.VerifySignatureRequest
-		err = c.unmarshalRequest(&request)
-		if err == nil {
-			c.logger.Debugf("executing %s plugin's VerifySignature function", reflect.TypeOf(c.pl))
-			resp, err = c.pl.VerifySignature(ctx, &request)
+		if p, ok := c.pl.(plugin.VerifyPlugin); ok {
+			request, handler = &plugin.VerifySignatureRequest{}, handlerFor(p.VerifySignature)
 		}
 	case plugin.CommandDescribeKey:
-		var request plugin.DescribeKeyRequest
-		err = c.unmarshalRequest(&request)
-		if err == nil {
-			c.logger.Debugf("executing %s plugin's DescribeKey function", reflect.TypeOf(c.pl))
-			resp, err = c.pl.DescribeKey(ctx, &request)
+		if p, ok := c.pl.(plugin.SignatureGeneratorPlugin); ok {
+			request, handler = &plugin.DescribeKeyRequest{}, handlerFor(p.DescribeKey)
 		}
 	case plugin.CommandGenerateSignature:
-		var request plugin.GenerateSignatureRequest
-		err = c.unmarshalRequest(&request)
-		if err == nil {
-			c.logger.Debugf("executing %s plugin's GenerateSignature function", reflect.TypeOf(c.pl))
-			resp, err = c.pl.GenerateSignature(ctx, &request)
-		}
-	case plugin.Version:
-		rescueStdOut()
-		c.printVersion(ctx)
-		return
+		if p, ok := c.pl.(plugin.SignatureGeneratorPlugin); ok {
+			request, handler = &plugin.GenerateSignatureRequest{}, handlerFor(p.GenerateSignature)
+		}
+	case plugin.CommandGenerateSignatureBatch:
+		if p, ok := c.pl.(plugin.SignatureGeneratorPlugin); ok && c.batch {
+			request, handler = &plugin.GenerateSignatureBatchRequest{}, handlerFor(func(ctx context.Context, req *plugin.GenerateSignatureBatchRequest) (plugin.GenerateSignatureBatchResponse, error) {
+				return c.generateSignatureBatch(ctx, p, *req), nil
+			})
+		}
 	default:
+		if cmd, ok := c.customCommand(command); ok {
+			request, handler = cmd.newRequest(), cmd.handler
+		}
+	}
+	if handler == nil {
 		// should never happen
-		rescueStdOut()
-		deliverError(plugin.NewGenericError("something went wrong").Error())
+		return nil, plugin.NewGenericError("something went wrong")
 	}
 
-	op, pluginErr := c.marshalResponse(resp, err)
-	rescueStdOut()
-	if pluginErr != nil {
-		deliverError(pluginErr.Error())
+	if err := c.unmarshalRequest(stdin, request); err != nil {
+		return nil, err
+	}
+
+	if err := validateContractVersion(c.md, request); err != nil {
+		c.logger.Errorf("%s contract version error: %v", reflect.TypeOf(request), err)
+		return nil, err
 	}
-	fmt.Print(op)
-}
 
-// printVersion prints version of executable
-func (c *CLI) printVersion(ctx context.Context) {
-	md := c.getMetadata(ctx, c.pl)
+	if err := validateRequestedCapabilities(c.md, request); err != nil {
+		c.logger.Errorf("%s capability error: %v", reflect.TypeOf(request), err)
+		return nil, err
+	}
+
+	c.logger.Debugf("executing %s plugin's %s command", reflect.TypeOf(c.pl), command)
+	// an interceptor may replace the request, so the response is validated against the request the plugin
+	// function received, or the decoded request if an interceptor responded without calling the plugin function
+	received := request
+	resp, err = c.chain(command, func(ctx context.Context, req plugin.Request) (any, error) {
+		received = req
+		return handler(ctx, req)
+	})(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateResponse(received, resp); err != nil {
+		c.logger.Errorf("%s validation error: %v", reflect.TypeOf(resp), err)
+		var plError *plugin.Error
+		if errors.As(err, &plError) {
+			return nil, plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, plError.Message)
+		}
+		return nil, plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, err.Error())
+	}
+	return resp, nil
+}
 
-	fmt.Printf("%s - %s\nVersion: %s\n", md.Name, md.Description, md.Version)
+// printVersion prints version of executable in the output format requested by given version flags.
+func printVersion(md *plugin.GetMetadataResponse, flags []string, w io.Writer) error {
+	output, err := parseVersionOutput(flags)
+	if err != nil {
+		return err
+	}
+	if output == versionOutputJSON {
+		return printVersionJSON(md, w)
+	}
+	_, _ = fmt.Fprintf(w, "%s - %s\nVersion: %s\n", md.Name, md.Description, md.Version)
+	return nil
 }
 
 // validateArgs validate commands/arguments passed to executable.
-func (c *CLI) validateArgs(ctx context.Context, args []string) {
-	md := c.getMetadata(ctx, c.pl)
-	if !(len(args) == 2 && slices.Contains(getValidArgs(md), args[1])) {
-		deliverError(fmt.Sprintf("Invalid command, valid commands are: %s", getValidArgsString(md)))
+// Only the version command accepts flags, which are validated by printVersion.
+func (c *CLI) validateArgs(args []string) error {
+	validArgs := c.validArgs()
+	if !(len(args) >= 2 && slices.Contains(validArgs, args[1]) && (len(args) == 2 || args[1] == string(plugin.Version))) {
+		return fmt.Errorf("Invalid command, valid commands are: %s", getValidArgsString(validArgs))
 	}
+	return validateExecutableName(c.md, args[0])
 }
 
-// unmarshalRequest reads input from std.in and unmarshal it into given request struct
-func (c *CLI) unmarshalRequest(request plugin.Request) error {
-	if err := json.NewDecoder(os.Stdin).Decode(request); err != nil {
+// unmarshalRequest reads input from given reader and unmarshal it into given request struct
+func (c *CLI) unmarshalRequest(r io.Reader, request plugin.Request) error {
+	if batch, ok := request.(*plugin.GenerateSignatureBatchRequest); ok {
+		if err := c.decodeBatch(r, batch); err != nil {
+			return err
+		}
+	} else if c.strict {
+		if err := c.decodeStrict(r, request); err != nil {
+			return err
+		}
+	} else if err := json.NewDecoder(r).Decode(request); err != nil {
 		c.logger.Errorf("%s unmarshalling error: %v", reflect.TypeOf(request), err)
 		return plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
 	}
 
 	if err := request.Validate(); err != nil {
 		c.logger.Errorf("%s validation error: %v", reflect.TypeOf(request), err)
-		var plError *plugin.Error
-		if errors.As(err, &plError) {
-			return plugin.NewValidationErrorf("%s: %s", plugin.ErrorMsgMalformedInput, plError.Message)
+		return malformedInputError(err)
+	}
+
+	return nil
+}
+
+// malformedInputError converts given request validation error into a validation plugin error.
+func malformedInputError(err error) *plugin.Error {
+	var plError *plugin.Error
+	if errors.As(err, &plError) {
+		return plugin.NewValidationErrorf("%s: %s", plugin.ErrorMsgMalformedInput, plError.Message)
+	}
+	return plugin.NewValidationErrorf("%s", plugin.ErrorMsgMalformedInput)
+}
+
+// decodeStrict reads input from given reader and unmarshal it into given request struct, rejecting unknown fields,
+// trailing data and input exceeding the maximum request size.
+func (c *CLI) decodeStrict(r io.Reader, request plugin.Request) error {
+	r, err := c.limitRequest(r, request)
+	if err != nil {
+		return err
+	}
+
+	var input bytes.Buffer
+	dec := json.NewDecoder(io.TeeReader(r, &input))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(request); err != nil {
+		c.logger.Errorf("%s unmarshalling error: %v", reflect.TypeOf(request), err)
+		if path, ok := unknownFieldPath(input.Bytes(), reflect.TypeOf(request)); ok {
+			return plugin.NewValidationErrorf("%s: unknown field %q", plugin.ErrorMsgMalformedInput, path)
 		}
-		return plugin.NewValidationErrorf("%s", plugin.ErrorMsgMalformedInput)
+		return plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
 	}
 
+	if _, err := dec.Token(); err != io.EOF {
+		c.logger.Errorf("%s unmarshalling error: unexpected data after JSON value", reflect.TypeOf(request))
+		return plugin.NewValidationErrorf("%s: unexpected data after JSON value", plugin.ErrorMsgMalformedInput)
+	}
 	return nil
 }
 
-func (c *CLI) getMetadata(ctx context.Context, p plugin.Plugin) *plugin.GetMetadataResponse {
-	md, err := p.GetMetadata(ctx, &plugin.GetMetadataRequest{})
+// limitRequest reads given request input and fails if it exceeds the maximum request size, if any.
+func (c *CLI) limitRequest(r io.Reader, request plugin.Request) (io.Reader, error) {
+	if c.maxRequestSize <= 0 {
+		return r, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r, c.maxRequestSize+1))
+	if err != nil {
+		c.logger.Errorf("%s reading error: %v", reflect.TypeOf(request), err)
+		return nil, plugin.NewJSONParsingError(plugin.ErrorMsgMalformedInput)
+	}
+	if int64(len(data)) > c.maxRequestSize {
+		c.logger.Errorf("%s exceeds maximum request size of %d bytes", reflect.TypeOf(request), c.maxRequestSize)
+		return nil, plugin.NewValidationErrorf("%s: request exceeds maximum size of %d bytes", plugin.ErrorMsgMalformedInput, c.maxRequestSize)
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (c *CLI) getMetadata(ctx context.Context, pluginConfig map[string]string) (md *plugin.GetMetadataResponse, err error) {
+	defer c.recoverPanic(&err)
+
+	md, err = c.pl.GetMetadata(ctx, &plugin.GetMetadataRequest{PluginConfig: pluginConfig})
 	if err != nil {
 		c.logger.Errorf("GetMetadataRequest error: %v", err)
-		deliverError("Error: Failed to get plugin metadata.")
+		return nil, err
+	}
+	return md, nil
+}
+
+// recoverPanic recovers from a panic raised by the plugin and converts it into a generic plugin error, so that
+// notation always receives a well-formed error response. It must be called directly by a defer statement.
+func (c *CLI) recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		c.logger.Errorf("plugin panicked: %v\n%s", r, debug.Stack())
+		*err = plugin.NewGenericErrorf("plugin panicked: %v", r)
 	}
-	return md
 }
 
 // marshalResponse marshals the given response struct into json
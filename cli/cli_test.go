@@ -14,6 +14,7 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -23,13 +24,16 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
+// pluginExecutable is the path of the plugin executable passed as first argument to the CLI.
+const pluginExecutable = "/home/user/.config/notation/plugins/com.example.plugin/notation-com.example.plugin"
+
 var cli, _ = New(mock.NewPlugin(false))
-var errorCli, _ = New(mock.NewPlugin(true))
 
 func TestNewWithLogger(t *testing.T) {
 	_, err := NewWithLogger(nil, &discardLogger{})
@@ -68,11 +72,9 @@ func TestMarshalResponseError(t *testing.T) {
 
 func TestUnmarshalRequest(t *testing.T) {
 	content := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"pluginConfig\":{\"pc1\":\"pk1\"}}"
-	closer := setupReader(content)
-	defer closer()
 
 	var request plugin.DescribeKeyRequest
-	if err := cli.unmarshalRequest(&request); err != nil {
+	if err := cli.unmarshalRequest(strings.NewReader(content), &request); err != nil {
 		t.Errorf("unmarshalRequest() failed with error: %v", err)
 	}
 
@@ -82,11 +84,8 @@ func TestUnmarshalRequest(t *testing.T) {
 }
 
 func TestUnmarshalRequestError(t *testing.T) {
-	closer := setupReader("InvalidJson")
-	defer closer()
-
 	var request plugin.DescribeKeyRequest
-	err := cli.unmarshalRequest(&request)
+	err := cli.unmarshalRequest(strings.NewReader("InvalidJson"), &request)
 	if err == nil {
 		t.Errorf("unmarshalRequest() expected error but not found")
 	}
@@ -102,13 +101,129 @@ func TestUnmarshalRequestError(t *testing.T) {
 	}
 }
 
-func TestGetMetadataError(t *testing.T) {
+func TestUnmarshalRequestStrict(t *testing.T) {
+	strictCli, _ := New(mock.NewPlugin(false), WithStrictDecoding(64))
+	content := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}\n"
+
+	var request plugin.DescribeKeyRequest
+	if err := strictCli.unmarshalRequest(strings.NewReader(content), &request); err != nil {
+		t.Errorf("unmarshalRequest() failed with error: %v", err)
+	}
+
+	if request.ContractVersion != "1.0" || request.KeyID != "someKeyId" {
+		t.Errorf("unmarshalRequest() returned incorrect struct")
+	}
+}
+
+func TestUnmarshalRequestStrictError(t *testing.T) {
+	strictCli, _ := New(mock.NewPlugin(false), WithStrictDecoding(64))
+	tests := map[string]struct {
+		in  string
+		err string
+	}{
+		"unknownField": {
+			in:  "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"sad\":\"bad\"}",
+			err: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: unknown field \\\"sad\\\"\"}",
+		},
+		"trailingData": {
+			in:  "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}garbage",
+			err: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: unexpected data after JSON value\"}",
+		},
+		"trailingValue": {
+			in:  "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"} {}",
+			err: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: unexpected data after JSON value\"}",
+		},
+		"tooLarge": {
+			in:  "{\"contractVersion\":\"1.0\",\"keyId\":\"someVeryVeryVeryVeryVeryLongKeyId\"}",
+			err: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: request exceeds maximum size of 64 bytes\"}",
+		},
+		"invalidJson": {
+			in:  "InvalidJson",
+			err: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var request plugin.DescribeKeyRequest
+			err := strictCli.unmarshalRequest(strings.NewReader(test.in), &request)
+			if err == nil {
+				t.Fatalf("unmarshalRequest() expected error but not found")
+			}
+			if err.Error() != test.err {
+				t.Errorf("unmarshalRequest() expected error '%s' but found '%s'", test.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestNewMetadataError(t *testing.T) {
+	tests := map[string]struct {
+		pl     plugin.GenericPlugin
+		stderr string
+	}{
+		"error": {
+			pl:     mock.NewPlugin(true),
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"GetMetadata() expected error\"}",
+		},
+		"panic": {
+			pl:     &metadataPlugin{},
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin panicked: GetMetadata() panicked\"}",
+		},
+		"invalidMetadata": {
+			pl: &metadataPlugin{md: &plugin.GetMetadataResponse{
+				Name:                      "com.example.plugin",
+				Version:                   "v1",
+				URL:                       "https://example.com/notation/plugin",
+				SupportedContractVersions: []string{plugin.ContractVersion},
+				Capabilities:              []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier},
+			}},
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"invalid plugin metadata: version \\\"v1\\\" is not a valid semantic version\"}",
+		},
+		"unimplementedCapability": {
+			pl: &metadataPlugin{md: &plugin.GetMetadataResponse{
+				Name:                      "com.example.plugin",
+				Version:                   "1.0.0",
+				URL:                       "https://example.com/notation/plugin",
+				SupportedContractVersions: []string{plugin.ContractVersion},
+				Capabilities:              []plugin.Capability{plugin.CapabilitySignatureGenerator},
+			}},
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin declares capability \\\"SIGNATURE_GENERATOR.RAW\\\" but doesn't implement plugin.SignatureGeneratorPlugin\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(test.pl); err == nil || err.Error() != test.stderr {
+				t.Errorf("New() expected error '%s' but found '%v'", test.stderr, err)
+			}
+
+			// with the doctor command enabled, the other commands return the error
+			c, err := New(test.pl, WithDoctor())
+			if err != nil {
+				t.Fatalf("New() failed with error: %v", err)
+			}
+			for _, args := range [][]string{{pluginExecutable, string(plugin.Version)}, {pluginExecutable, "help"}} {
+				var stdout, stderr bytes.Buffer
+				if code := c.Run(context.Background(), args, strings.NewReader(""), &stdout, &stderr); code != 1 {
+					t.Errorf("Run() expected exit code 1 but got %d", code)
+				}
+				if stdout.Len() != 0 {
+					t.Errorf("Run() expected empty stdout but got '%s'", stdout.String())
+				}
+				if stderr.String() != test.stderr {
+					t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteError(t *testing.T) {
 	if os.Getenv("TEST_OS_EXIT") == "1" {
 		ctx := context.Background()
-		errorCli.Execute(ctx, []string{string(plugin.CommandGetMetadata)})
+		cli.Execute(ctx, []string{pluginExecutable, "invalid"})
 		return
 	}
-	cmd := exec.Command(os.Args[0], "-test.run=TestGetMetadataError")
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteError")
 	cmd.Env = append(os.Environ(), "TEST_OS_EXIT=1")
 	err := cmd.Run()
 	if e, ok := err.(*exec.ExitError); ok && !e.Success() {
@@ -127,26 +242,26 @@ func TestExecuteSuccess(t *testing.T) {
 		string(plugin.CommandGetMetadata): {
 			c:  cli,
 			in: "{}",
-			op: "{\"name\":\"Example Plugin\",\"description\":\"This is an description of example plugin. 🍺\",\"version\":\"1.0.0\",\"url\":\"https://example.com/notation/plugin\",\"capabilities\":[\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\",\"SIGNATURE_GENERATOR.ENVELOPE\"]}",
+			op: "{\"name\":\"com.example.plugin\",\"description\":\"This is an description of example plugin. 🍺\",\"version\":\"1.0.0\",\"url\":\"https://example.com/notation/plugin\",\"supportedContractVersions\":[\"1.0\"],\"capabilities\":[\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\",\"SIGNATURE_GENERATOR.ENVELOPE\"]}",
 		},
 		string(plugin.Version): {
 			c:  cli,
 			in: "",
-			op: "Example Plugin - This is an description of example plugin. 🍺\nVersion: 1.0.0\n",
+			op: "com.example.plugin - This is an description of example plugin. 🍺\nVersion: 1.0.0\n",
 		},
 		string(plugin.CommandGenerateEnvelope): {
 			c:  cli,
 			in: "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"payloadType\":\"somePT\",\"signatureEnvelopeType\":\"someSET\",\"payload\":\"em9w\"}",
-			op: "{\"signatureEnvelope\":\"\",\"signatureEnvelopeType\":\"someSET\",\"annotations\":{\"manifestAnntnKey1\":\"value1\"}}",
+			op: "{\"signatureEnvelope\":\"ZW52ZWxvcGU=\",\"signatureEnvelopeType\":\"someSET\",\"annotations\":{\"manifestAnntnKey1\":\"value1\"}}",
 		},
 		string(plugin.CommandVerifySignature): {
 			c:  cli,
-			in: "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"someCT\",\"signingScheme\":\"someSigningScheme\"},\"unprocessedAttributes\":null,\"certificateChain\":[\"emFw\",\"em9w\"]},\"trustPolicy\":{\"trustedIdentities\":null,\"signatureVerification\":[\"SIGNATURE_GENERATOR.RAW\"]}}",
+			in: "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"someCT\",\"signingScheme\":\"someSigningScheme\"},\"unprocessedAttributes\":null,\"certificateChain\":[\"emFw\",\"em9w\"]},\"trustPolicy\":{\"trustedIdentities\":null,\"signatureVerification\":[\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\"]}}",
 			op: "{\"verificationResults\":{\"SIGNATURE_VERIFIER.REVOCATION_CHECK\":{\"success\":true,\"reason\":\"Not revoked\"},\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\":{\"success\":true,\"reason\":\"Valid trusted Identity\"}},\"processedAttributes\":[]}",
 		},
 		string(plugin.CommandGenerateSignature): {
 			c:  sigGenCli,
-			in: "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"EC-384\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}",
+			in: "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\"}",
 			op: "{\"keyId\":\"someKeyId\",\"signature\":\"YWJjZA==\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]}",
 		},
 		string(plugin.CommandDescribeKey): {
@@ -160,7 +275,7 @@ func TestExecuteSuccess(t *testing.T) {
 			closer := setupReader(test.in)
 			defer closer()
 			op := captureStdOut(func() {
-				test.c.Execute(context.Background(), []string{"notation", name})
+				test.c.Execute(context.Background(), []string{pluginExecutable, name})
 			})
 			if op != test.op {
 				t.Errorf("Execute() with '%s' args, expected '%s' but got '%s'", name, test.op, op)
@@ -169,6 +284,257 @@ func TestExecuteSuccess(t *testing.T) {
 	}
 }
 
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false))
+	if code := sigGenCli.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+
+	expected := "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\"}"
+	if stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("Run() expected empty stderr but got '%s'", stderr.String())
+	}
+}
+
+func TestCall(t *testing.T) {
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false))
+	op, err := sigGenCli.Call(context.Background(), plugin.CommandDescribeKey, []byte("{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"))
+	if err != nil {
+		t.Fatalf("Call() failed with error: %v", err)
+	}
+	if expected := "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\"}"; string(op) != expected {
+		t.Errorf("Call() expected response '%s' but got '%s'", expected, op)
+	}
+
+	tests := map[string]struct {
+		command  plugin.Command
+		request  string
+		expected *plugin.Error
+	}{
+		"unsupportedCommand": {
+			command:  plugin.CommandGenerateEnvelope,
+			request:  "{}",
+			expected: plugin.NewGenericError("command \"generate-envelope\" not found"),
+		},
+		"unsupportedContractVersion": {
+			command:  plugin.CommandDescribeKey,
+			request:  "{\"contractVersion\":\"2.0\",\"keyId\":\"someKeyId\"}",
+			expected: plugin.NewUnsupportedContractVersionError("2.0"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := sigGenCli.Call(context.Background(), test.command, []byte(test.request)); !reflect.DeepEqual(err, test.expected) {
+				t.Errorf("Call() expected error %v but got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestRunError(t *testing.T) {
+	emptyRespCli, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(func(_ context.Context, _ plugin.Command, _ plugin.Request, _ Handler) (any, error) {
+		return nil, nil
+	}))
+	tests := map[string]struct {
+		c      *CLI
+		args   []string
+		in     string
+		stderr string
+	}{
+		"invalidCommand": {
+			c:      cli,
+			args:   []string{pluginExecutable, "invalid"},
+			stderr: "Invalid command, valid commands are: <generate-envelope|get-plugin-metadata|verify-signature|version>",
+		},
+		"commandArgs": {
+			c:      cli,
+			args:   []string{pluginExecutable, string(plugin.CommandGetMetadata), "--output", "json"},
+			stderr: "Invalid command, valid commands are: <generate-envelope|get-plugin-metadata|verify-signature|version>",
+		},
+		"invalidVersionOutput": {
+			c:      cli,
+			args:   []string{pluginExecutable, string(plugin.Version), "--output", "yaml"},
+			stderr: "Invalid version output \"yaml\", valid outputs are: <json|text>",
+		},
+		"invalidExecutableName": {
+			c:      cli,
+			args:   []string{"/usr/bin/notation-other.plugin", string(plugin.CommandGetMetadata)},
+			in:     "{}",
+			stderr: "Invalid plugin executable name \"notation-other.plugin\", expected \"notation-com.example.plugin\"",
+		},
+		"invalidInput": {
+			c:      cli,
+			args:   []string{pluginExecutable, string(plugin.CommandGenerateEnvelope)},
+			in:     "InvalidJson",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON\"}",
+		},
+		"unsupportedContractVersion": {
+			c:      cli,
+			args:   []string{pluginExecutable, string(plugin.CommandGenerateEnvelope)},
+			in:     "{\"contractVersion\":\"2.0\",\"keyId\":\"someKeyId\",\"payloadType\":\"somePT\",\"signatureEnvelopeType\":\"someSET\",\"payload\":\"em9w\"}",
+			stderr: "{\"errorCode\":\"UNSUPPORTED_CONTRACT_VERSION\",\"errorMessage\":\"\\\"2.0\\\" is not a supported notary plugin contract version\"}",
+		},
+		"nonVerifierCapability": {
+			c:      cli,
+			args:   []string{pluginExecutable, string(plugin.CommandVerifySignature)},
+			in:     "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"someCT\",\"signingScheme\":\"someSigningScheme\"},\"unprocessedAttributes\":null,\"certificateChain\":[\"emFw\",\"em9w\"]},\"trustPolicy\":{\"trustedIdentities\":null,\"signatureVerification\":[\"SIGNATURE_GENERATOR.RAW\"]}}",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: signature's trustPolicy's signatureVerification contains \\\"SIGNATURE_GENERATOR.RAW\\\", which is not a verifier capability\"}",
+		},
+		"invalidResponse": {
+			c:      emptyRespCli,
+			args:   []string{pluginExecutable, string(plugin.CommandDescribeKey)},
+			in:     "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}",
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"Failed to generate response. Error: response cannot be empty\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := test.c.Run(context.Background(), test.args, strings.NewReader(test.in), &stdout, &stderr); code == 0 {
+				t.Errorf("Run() expected nonzero exit code")
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("Run() expected empty stdout but got '%s'", stdout.String())
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"
+	tests := map[string]struct {
+		code      plugin.ErrorCode
+		exitCodes map[plugin.ErrorCode]int
+		expected  int
+	}{
+		"generic":                    {code: plugin.ErrorCodeGeneric, expected: 1},
+		"validation":                 {code: plugin.ErrorCodeValidation, expected: 2},
+		"accessDenied":               {code: plugin.ErrorCodeAccessDenied, expected: 3},
+		"timeout":                    {code: plugin.ErrorCodeTimeout, expected: 4},
+		"throttled":                  {code: plugin.ErrorCodeThrottled, expected: 5},
+		"unsupportedContractVersion": {code: plugin.ErrorCodeUnsupportedContractVersion, expected: 6},
+		"unknown":                    {code: "UNKNOWN", expected: 1},
+		"override":                   {code: plugin.ErrorCodeThrottled, exitCodes: map[plugin.ErrorCode]int{plugin.ErrorCodeThrottled: 75}, expected: 75},
+		"overrideGeneric":            {code: "UNKNOWN", exitCodes: map[plugin.ErrorCode]int{plugin.ErrorCodeGeneric: 10}, expected: 10},
+		"notOverridden":              {code: plugin.ErrorCodeTimeout, exitCodes: map[plugin.ErrorCode]int{plugin.ErrorCodeThrottled: 75}, expected: 4},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New(mock.NewSigGeneratorPlugin(false), WithExitCodes(test.exitCodes), WithInterceptors(func(_ context.Context, _ plugin.Command, _ plugin.Request, _ Handler) (any, error) {
+				return nil, plugin.NewError(test.code, "failed")
+			}))
+			if err != nil {
+				t.Fatalf("New() failed with error: %v", err)
+			}
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code != test.expected {
+				t.Errorf("Run() expected exit code %d but got %d", test.expected, code)
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithExitCodes(map[plugin.ErrorCode]int{plugin.ErrorCodeGeneric: 10}))
+	if code := c.Run(context.Background(), []string{pluginExecutable, "invalid"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Run() expected exit code 1 for invalid command but got %d", code)
+	}
+}
+
+func TestNewInvalidExitCode(t *testing.T) {
+	for _, exitCode := range []int{0, -1, 256} {
+		if _, err := New(mock.NewSigGeneratorPlugin(false), WithExitCodes(map[plugin.ErrorCode]int{plugin.ErrorCodeThrottled: exitCode})); err == nil {
+			t.Errorf("New() expected error for exit code %d", exitCode)
+		}
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	tests := map[string]struct {
+		pl     plugin.Plugin
+		args   []string
+		in     string
+		stderr string
+	}{
+		string(plugin.CommandGenerateSignature): {
+			pl:     &panicPlugin{Plugin: mock.NewSigGeneratorPlugin(false)},
+			args:   []string{pluginExecutable, string(plugin.CommandGenerateSignature)},
+			in:     "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"EC-384\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}",
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin panicked: GenerateSignature() panicked\"}",
+		},
+		string(plugin.CommandGetMetadata): {
+			pl:     &panicPlugin{Plugin: mock.NewPlugin(false), panicMetadata: true},
+			args:   []string{pluginExecutable, string(plugin.CommandGetMetadata)},
+			in:     "{\"pluginConfig\":{\"key\":\"value\"}}",
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"plugin panicked: GetMetadata() panicked\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _ := New(test.pl)
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), test.args, strings.NewReader(test.in), &stdout, &stderr); code == 0 {
+				t.Errorf("Run() expected nonzero exit code")
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("Run() expected empty stdout but got '%s'", stdout.String())
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"EC-384\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}"
+	args := []string{pluginExecutable, string(plugin.CommandGenerateSignature)}
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := map[string]struct {
+		ctx    context.Context
+		opts   []Option
+		stderr string
+	}{
+		"deadline": {
+			ctx:    context.Background(),
+			opts:   []Option{WithTimeout(10 * time.Millisecond)},
+			stderr: "{\"errorCode\":\"TIMEOUT\",\"errorMessage\":\"plugin command timed out\"}",
+		},
+		"cancelled": {
+			ctx:    cancelledCtx,
+			stderr: "{\"errorCode\":\"TIMEOUT\",\"errorMessage\":\"plugin command was cancelled\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unblock := make(chan struct{})
+			defer close(unblock)
+			c, _ := New(&slowPlugin{Plugin: mock.NewSigGeneratorPlugin(false), unblock: unblock}, test.opts...)
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(test.ctx, args, strings.NewReader(in), &stdout, &stderr); code == 0 {
+				t.Errorf("Run() expected nonzero exit code")
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("Run() expected empty stdout but got '%s'", stdout.String())
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
 func setupReader(content string) func() {
 	tmpfile, err := os.CreateTemp("", "example")
 	if err != nil {
@@ -204,6 +570,47 @@ func captureStdOut(f func()) string {
 	return string(out)
 }
 
+// panicPlugin wraps a plugin and panics when GenerateSignature is invoked. If panicMetadata is set, it also panics
+// when GetMetadata is invoked with plugin config, i.e. not by the CLI at construction time.
+type panicPlugin struct {
+	plugin.Plugin
+	panicMetadata bool
+}
+
+func (p *panicPlugin) GenerateSignature(_ context.Context, _ *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	panic("GenerateSignature() panicked")
+}
+
+func (p *panicPlugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	if p.panicMetadata && req.PluginConfig != nil {
+		panic("GetMetadata() panicked")
+	}
+	return p.Plugin.GetMetadata(ctx, req)
+}
+
+// slowPlugin wraps a plugin and blocks GenerateSignature until unblock is closed, ignoring the context.
+type slowPlugin struct {
+	plugin.Plugin
+	unblock chan struct{}
+}
+
+func (p *slowPlugin) GenerateSignature(ctx context.Context, req *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	<-p.unblock
+	return p.Plugin.GenerateSignature(ctx, req)
+}
+
+// metadataPlugin implements only plugin.GenericPlugin and panics if md isn't set.
+type metadataPlugin struct {
+	md *plugin.GetMetadataResponse
+}
+
+func (p *metadataPlugin) GetMetadata(_ context.Context, _ *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	if p.md == nil {
+		panic("GetMetadata() panicked")
+	}
+	return p.md, nil
+}
+
 func assertErr(t *testing.T, err error, code plugin.ErrorCode) {
 	if plgErr, ok := err.(*plugin.Error); ok {
 		if reflect.DeepEqual(code, plgErr.ErrCode) {
@@ -213,3 +620,34 @@ func assertErr(t *testing.T, err error, code plugin.ErrorCode) {
 	}
 	t.Errorf("expected error of type PluginError but found %s", reflect.TypeOf(err))
 }
+
+func TestRunAbandonedCalls(t *testing.T) {
+	unblock := make(chan struct{})
+	c, _ := New(&slowPlugin{Plugin: mock.NewSigGeneratorPlugin(false), unblock: unblock}, WithTimeout(10*time.Millisecond))
+	args := []string{pluginExecutable, string(plugin.CommandGenerateSignature)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"EC-384\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}"
+	var stdout, stderr bytes.Buffer
+	c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr)
+	if n := c.abandoned.Load(); n != 1 {
+		t.Errorf("expected 1 abandoned call but got %d", n)
+	}
+
+	c.abandoned.Store(maxAbandonedCalls)
+	stderr.Reset()
+	if code := c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code == 0 {
+		t.Errorf("Run() expected nonzero exit code")
+	}
+	expected := "{\"errorCode\":\"THROTTLED\",\"errorMessage\":\"too many plugin calls are still running after being interrupted\"}"
+	if stderr.String() != expected {
+		t.Errorf("Run() expected stderr '%s' but got '%s'", expected, stderr.String())
+	}
+
+	c.abandoned.Store(1)
+	close(unblock)
+	for i := 0; i < 100 && c.abandoned.Load() != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := c.abandoned.Load(); n != 0 {
+		t.Errorf("expected no abandoned call once the plugin returned but got %d", n)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// commandNameRegex matches valid custom command names, i.e. lowercase words separated by hyphens.
+var commandNameRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// reservedCommands contains the names that can't be used by custom commands.
+var reservedCommands = []string{
+	string(plugin.CommandGetMetadata),
+	string(plugin.CommandDescribeKey),
+	string(plugin.CommandGenerateSignature),
+	string(plugin.CommandGenerateEnvelope),
+	string(plugin.CommandVerifySignature),
+	string(plugin.CommandGenerateSignatureBatch),
+	string(plugin.Version),
+	serveCommand,
+	completionCommand,
+	doctorCommand,
+	"help",
+}
+
+// command is a vendor-specific command registered with WithCommand.
+type command struct {
+	name        string
+	description string
+	newRequest  func() plugin.Request
+	handler     Handler
+}
+
+// WithCommand registers a vendor-specific command with given name and one-line description, e.g. an operator-facing
+// helper such as "list-keys". The command reads its request from stdin and writes its response to stdout like the
+// plugin contract commands: the request is unmarshalled and validated, the handler is invoked through the
+// configured interceptors, and the response or error is marshalled.
+// The name must consist of lowercase words separated by hyphens and must not be the name of a contract command.
+// Custom commands are listed separately from contract commands in the help output.
+func WithCommand[Req any, PReq interface {
+	*Req
+	plugin.Request
+}, Resp any](name, description string, f func(context.Context, PReq) (Resp, error)) Option {
+	return func(c *CLI) {
+		c.commands = append(c.commands, command{
+			name:        name,
+			description: description,
+			newRequest:  func() plugin.Request { return PReq(new(Req)) },
+			handler:     handlerFor(f),
+		})
+	}
+}
+
+// validateCommands checks that the names of given custom commands are valid and unique.
+func validateCommands(commands []command) error {
+	names := make(map[string]bool, len(commands))
+	for _, cmd := range commands {
+		if !commandNameRegex.MatchString(cmd.name) {
+			return fmt.Errorf("invalid command name %q, expected lowercase words separated by hyphens", cmd.name)
+		}
+		if slices.Contains(reservedCommands, cmd.name) {
+			return fmt.Errorf("command name %q is reserved", cmd.name)
+		}
+		if names[cmd.name] {
+			return fmt.Errorf("command %q is registered more than once", cmd.name)
+		}
+		names[cmd.name] = true
+	}
+	return nil
+}
+
+// customCommand returns the custom command with given name, if any.
+func (c *CLI) customCommand(name plugin.Command) (command, bool) {
+	for _, cmd := range c.commands {
+		if cmd.name == string(name) {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// vendorCommands returns the sorted names of the enabled commands that aren't part of the plugin contract, i.e.
+// the generate-signature-batch and doctor commands and the custom commands.
+func (c *CLI) vendorCommands() []string {
+	var names []string
+	if c.batch {
+		names = append(names, string(plugin.CommandGenerateSignatureBatch))
+	}
+	if c.doctor {
+		names = append(names, doctorCommand)
+	}
+	for _, cmd := range c.commands {
+		names = append(names, cmd.name)
+	}
+	sort.Strings(names)
+	return names
+}
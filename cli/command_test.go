@@ -0,0 +1,148 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const commandListKeys plugin.Command = "list-keys"
+
+type listKeysRequest struct {
+	Prefix string `json:"prefix"`
+}
+
+func (listKeysRequest) Command() plugin.Command {
+	return commandListKeys
+}
+
+func (r listKeysRequest) Validate() error {
+	if r.Prefix == "" {
+		return plugin.NewValidationError("prefix cannot be empty")
+	}
+	return nil
+}
+
+type listKeysResponse struct {
+	KeyIDs []string `json:"keyIds"`
+}
+
+func listKeys(_ context.Context, req *listKeysRequest) (*listKeysResponse, error) {
+	if req.Prefix == "denied" {
+		return nil, plugin.NewError(plugin.ErrorCodeAccessDenied, "access denied")
+	}
+	return &listKeysResponse{KeyIDs: []string{req.Prefix + "-1", req.Prefix + "-2"}}, nil
+}
+
+func TestRunCustomCommand(t *testing.T) {
+	var intercepted []plugin.Command
+	c, err := New(mock.NewSigGeneratorPlugin(false),
+		WithCommand("list-keys", "Lists the keys with the given prefix", listKeys),
+		WithInterceptors(func(ctx context.Context, command plugin.Command, req plugin.Request, next Handler) (any, error) {
+			intercepted = append(intercepted, command)
+			return next(ctx, req)
+		}))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	tests := map[string]struct {
+		in       string
+		exitCode int
+		stdout   string
+		stderr   string
+	}{
+		"success": {
+			in:     "{\"prefix\":\"key\"}",
+			stdout: "{\"keyIds\":[\"key-1\",\"key-2\"]}",
+		},
+		"invalidRequest": {
+			in:       "{}",
+			exitCode: 2,
+			stderr:   "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: prefix cannot be empty\"}",
+		},
+		"malformedRequest": {
+			in:       "{",
+			exitCode: 2,
+			stderr:   "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON\"}",
+		},
+		"commandError": {
+			in:       "{\"prefix\":\"denied\"}",
+			exitCode: 3,
+			stderr:   "{\"errorCode\":\"ACCESS_DENIED\",\"errorMessage\":\"access denied\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), []string{pluginExecutable, "list-keys"}, strings.NewReader(test.in), &stdout, &stderr); code != test.exitCode {
+				t.Errorf("Run() expected exit code %d but got %d", test.exitCode, code)
+			}
+			if stdout.String() != test.stdout {
+				t.Errorf("Run() expected stdout '%s' but got '%s'", test.stdout, stdout.String())
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+	if len(intercepted) != 2 || intercepted[0] != commandListKeys {
+		t.Errorf("expected interceptor to be invoked twice for %s but got %v", commandListKeys, intercepted)
+	}
+
+	var stdout, stderr bytes.Buffer
+	c.Run(context.Background(), []string{pluginExecutable, "invalid"}, strings.NewReader(""), &stdout, &stderr)
+	expected := "Invalid command, valid commands are: <describe-key|generate-signature|get-plugin-metadata|list-keys|verify-signature|version>"
+	if stderr.String() != expected {
+		t.Errorf("Run() expected stderr '%s' but got '%s'", expected, stderr.String())
+	}
+}
+
+func TestRunHelpCustomCommand(t *testing.T) {
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(1), WithCommand("list-keys", "Lists the keys with the given prefix", listKeys))
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), []string{pluginExecutable, "help"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d", code)
+	}
+	expected := "  version                  Prints the plugin version, use \"--output json\" for machine-readable output\n\n" +
+		"Vendor Commands:\n" +
+		"  generate-signature-batch Signs a batch of generate-signature requests and returns the result of each request\n" +
+		"                           stdin: " + commandHelps[string(plugin.CommandGenerateSignatureBatch)].stdin + "\n" +
+		"  list-keys                Lists the keys with the given prefix\n"
+	if !strings.HasSuffix(stdout.String(), expected) {
+		t.Errorf("Run() expected stdout ending with '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestNewInvalidCommand(t *testing.T) {
+	tests := map[string][]Option{
+		"emptyName":   {WithCommand("", "description", listKeys)},
+		"invalidName": {WithCommand("List_Keys", "description", listKeys)},
+		"reserved":    {WithCommand("describe-key", "description", listKeys)},
+		"serve":       {WithCommand("serve", "description", listKeys)},
+		"duplicate":   {WithCommand("list-keys", "description", listKeys), WithCommand("list-keys", "description", listKeys)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(mock.NewSigGeneratorPlugin(false), opts...); err == nil {
+				t.Error("New() expected error for invalid custom command")
+			}
+		})
+	}
+}
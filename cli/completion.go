@@ -0,0 +1,232 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// completionCommand is the name of the hidden command printing shell completion scripts.
+const completionCommand = "completion"
+
+// completionTemplates contains the completion script templates of the supported shells.
+var completionTemplates = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Parse(`# bash completion for {{.Program}}
+{{.Function}}() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "{{range $i, $c := .Commands}}{{if $i}} {{end}}{{$c.Name}}{{end}}" -- "${cur}"))
+        return
+    fi
+    case "${COMP_WORDS[1]}" in
+{{- range .Flags}}
+    {{.Command}})
+        case "${prev}" in
+{{- range .Flags}}
+        --{{.Name}})
+            COMPREPLY=({{if .Values}}$(compgen -W "{{.ValueList}}" -- "${cur}"){{else if .Files}}$(compgen -f -- "${cur}"){{end}})
+            ;;
+{{- end}}
+        *)
+            COMPREPLY=($(compgen -W "{{.FlagList}}" -- "${cur}"))
+            ;;
+        esac
+        ;;
+{{- end}}
+    esac
+}
+complete -F {{.Function}} {{.Program}}
+`)),
+	"zsh": template.Must(template.New("zsh").Parse(`#compdef {{.Program}}
+
+{{.Function}}() {
+    local -a commands
+    commands=(
+{{- range .Commands}}
+        '{{.ZshName}}:{{.ZshDescription}}'
+{{- end}}
+    )
+    if (( CURRENT == 2 )); then
+        _describe 'command' commands
+        return
+    fi
+    local command=${words[2]}
+    shift words
+    (( CURRENT-- ))
+    case ${command} in
+{{- range .Flags}}
+    {{.Command}})
+        _arguments{{range .Flags}} \
+            '{{if .Repeatable}}*{{end}}--{{.Name}}[{{.ZshDescription}}]:{{.Name}}:{{.ZshAction}}'{{end}}
+        ;;
+{{- end}}
+    esac
+}
+
+compdef {{.Function}} {{.Program}}
+`)),
+	"fish": template.Must(template.New("fish").Parse(`# fish completion for {{.Program}}
+complete -c {{.Program}} -f
+{{- range .Commands}}
+complete -c {{$.Program}} -n '__fish_use_subcommand' -a '{{.Name}}' -d '{{.FishDescription}}'
+{{- end}}
+{{- range $c := .Flags}}
+{{- range .Flags}}
+complete -c {{$.Program}} -n '__fish_seen_subcommand_from {{$c.Command}}' -l {{.Name}} {{if .Values}}-x -a '{{.ValueList}}'{{else if .Files}}-r -F{{else}}-x{{end}} -d '{{.FishDescription}}'
+{{- end}}
+{{- end}}
+`)),
+}
+
+// serveFlags and versionFlags contain the flags of the serve and version commands.
+var (
+	serveFlags = completionCommandFlags{Command: serveCommand, Flags: []completionFlag{
+		{Name: "socket", Description: "Path of the Unix domain socket to listen on", Files: true},
+	}}
+	versionFlags = completionCommandFlags{Command: string(plugin.Version), Flags: []completionFlag{
+		{Name: "output", Description: "Output format", Values: []string{versionOutputJSON, versionOutputText}},
+	}}
+)
+
+// nonIdentifierRegex matches the characters that can't be used in a shell function name.
+var nonIdentifierRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// completionScript contains the data used to render a completion script.
+type completionScript struct {
+	Program  string
+	Function string
+	Commands []completionCommandHelp
+	Flags    []completionCommandFlags
+}
+
+// completionCommandHelp is a command offered by a completion script.
+type completionCommandHelp struct {
+	Name        string
+	Description string
+}
+
+// ZshName returns the command name escaped for a zsh _describe specification.
+func (h completionCommandHelp) ZshName() string {
+	return strings.ReplaceAll(h.Name, ":", `\:`)
+}
+
+// ZshDescription returns the description escaped for a single-quoted zsh string.
+func (h completionCommandHelp) ZshDescription() string {
+	return zshQuote(h.Description)
+}
+
+// FishDescription returns the description escaped for a single-quoted fish string.
+func (h completionCommandHelp) FishDescription() string {
+	return fishQuote(h.Description)
+}
+
+// completionCommandFlags contains the flags of a command offered by a completion script.
+type completionCommandFlags struct {
+	Command string
+	Flags   []completionFlag
+}
+
+// FlagList returns the space-separated flags of the command.
+func (f completionCommandFlags) FlagList() string {
+	flags := make([]string, len(f.Flags))
+	for i, flag := range f.Flags {
+		flags[i] = "--" + flag.Name
+	}
+	return strings.Join(flags, " ")
+}
+
+// completionFlag is a flag offered by a completion script. Its value is completed using Values if set, as a file
+// path if Files is set, and isn't completed otherwise.
+type completionFlag struct {
+	Name        string
+	Description string
+	Values      []string
+	Files       bool
+	Repeatable  bool
+}
+
+// ValueList returns the space-separated values of the flag.
+func (f completionFlag) ValueList() string {
+	return strings.Join(f.Values, " ")
+}
+
+// ZshAction returns the zsh _arguments action completing the value of the flag.
+func (f completionFlag) ZshAction() string {
+	switch {
+	case len(f.Values) > 0:
+		return "(" + f.ValueList() + ")"
+	case f.Files:
+		return "_files"
+	}
+	return ""
+}
+
+// ZshDescription returns the description escaped for a zsh _arguments specification.
+func (f completionFlag) ZshDescription() string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(zshQuote(f.Description))
+}
+
+// FishDescription returns the description escaped for a single-quoted fish string.
+func (f completionFlag) FishDescription() string {
+	return fishQuote(f.Description)
+}
+
+// printCompletion prints the completion script of the shell requested by given completion arguments.
+// The script completes the commands supported by the CLI, including the vendor commands, along with their flags.
+func (c *CLI) printCompletion(args []string, w io.Writer) error {
+	shells := make([]string, 0, len(completionTemplates))
+	for shell := range completionTemplates {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	if len(args) != 1 || completionTemplates[args[0]] == nil {
+		return fmt.Errorf("Invalid completion arguments, expected one of: <%s>", strings.Join(shells, "|"))
+	}
+
+	program := plugin.BinaryPrefix + c.md.Name
+	script := completionScript{
+		Program:  program,
+		Function: "_" + nonIdentifierRegex.ReplaceAllString(program, "_"),
+		Flags:    []completionCommandFlags{serveFlags, versionFlags},
+	}
+	if c.doctor {
+		script.Flags = append(script.Flags, doctorFlags)
+	}
+	for _, arg := range c.validArgs() {
+		description := commandHelps[arg].description
+		if cmd, ok := c.customCommand(plugin.Command(arg)); ok {
+			description = cmd.description
+		}
+		script.Commands = append(script.Commands, completionCommandHelp{Name: arg, Description: description})
+	}
+	script.Commands = append(script.Commands, completionCommandHelp{Name: "help", Description: "Prints the usage of the plugin"})
+	return completionTemplates[args[0]].Execute(w, script)
+}
+
+// zshQuote escapes given string for a single-quoted zsh string.
+func zshQuote(s string) string {
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
+
+// fishQuote escapes given string for a single-quoted fish string.
+func fishQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
@@ -0,0 +1,108 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+)
+
+func TestRunCompletion(t *testing.T) {
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithCommand("list-keys", "Lists the keys of the 'vault'", listKeys))
+	tests := map[string][]string{
+		"bash": {
+			"complete -F _notation_com_example_plugin notation-com.example.plugin\n",
+			"compgen -W \"describe-key generate-signature get-plugin-metadata list-keys verify-signature version help\"",
+			"compgen -W \"json text\"",
+		},
+		"zsh": {
+			"#compdef notation-com.example.plugin\n",
+			"'list-keys:Lists the keys of the '\\''vault'\\'''\n",
+			"_arguments \\\n            '--output[Output format]:output:(json text)'",
+			"_arguments \\\n            '--socket[Path of the Unix domain socket to listen on]:socket:_files'",
+		},
+		"fish": {
+			"complete -c notation-com.example.plugin -n '__fish_use_subcommand' -a 'list-keys' -d 'Lists the keys of the \\'vault\\''\n",
+			"complete -c notation-com.example.plugin -n '__fish_seen_subcommand_from version' -l output -x -a 'json text' -d 'Output format'\n",
+		},
+	}
+	for shell, expected := range tests {
+		t.Run(shell, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), []string{pluginExecutable, "completion", shell}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+				t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+			}
+			for _, e := range expected {
+				if !strings.Contains(stdout.String(), e) {
+					t.Errorf("expected %s completion script to contain '%s' but got '%s'", shell, e, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunCompletionError(t *testing.T) {
+	for _, args := range [][]string{{}, {"powershell"}, {"bash", "zsh"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := cli.Run(context.Background(), append([]string{pluginExecutable, "completion"}, args...), strings.NewReader(""), &stdout, &stderr); code != 1 {
+				t.Errorf("Run() expected exit code 1 but got %d", code)
+			}
+			expected := "Invalid completion arguments, expected one of: <bash|fish|zsh>"
+			if stderr.String() != expected {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", expected, stderr.String())
+			}
+		})
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	var script bytes.Buffer
+	if err := cli.printCompletion([]string{"bash"}, &script); err != nil {
+		t.Fatalf("printCompletion() failed with error: %v", err)
+	}
+	tests := map[string]string{
+		"ge":                   "generate-envelope get-plugin-metadata",
+		"version --output ":    "json text",
+		"version --":           "--output",
+		"get-plugin-metadata ": "",
+	}
+	for line, expected := range tests {
+		t.Run(line, func(t *testing.T) {
+			words := strings.Split("notation-com.example.plugin "+line, " ")
+			program := script.String() + `
+COMP_WORDS=("$@")
+COMP_CWORD=$(($# - 1))
+_notation_com_example_plugin
+echo -n "${COMPREPLY[*]}"`
+			// bash -c assigns the argument following the program to $0
+			cmd := exec.Command("bash", append([]string{"-c", program, "bash"}, words...)...)
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("failed to run completion: %v", err)
+			}
+			if string(out) != expected {
+				t.Errorf("expected completion '%s' but got '%s'", expected, out)
+			}
+		})
+	}
+}
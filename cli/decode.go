@@ -0,0 +1,148 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unknownFieldPath returns the path of a field of the first JSON value of given data that doesn't match a field of
+// given type, e.g. "signature.criticalAttributes.foo" or "keys[0].foo". Object keys are walked in sorted order,
+// and fields are matched the way encoding/json matches them, i.e. using their JSON name case-insensitively.
+func unknownFieldPath(data []byte, t reflect.Type) (string, bool) {
+	var v any
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return "", false
+	}
+	return findUnknownField(v, t, "")
+}
+
+// findUnknownField walks given decoded JSON value along with given type and returns the path of the first
+// unknown field, prefixed with given path.
+func findUnknownField(v any, t reflect.Type, path string) (string, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		// custom unmarshalers decide which fields they accept
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for _, key := range sortedKeys(v) {
+				ft, ok := lookupField(fields, key)
+				if !ok {
+					return joinPath(path, key), true
+				}
+				if p, ok := findUnknownField(v[key], ft, joinPath(path, key)); ok {
+					return p, true
+				}
+			}
+		case reflect.Map:
+			for _, key := range sortedKeys(v) {
+				if p, ok := findUnknownField(v[key], t.Elem(), joinPath(path, key)); ok {
+					return p, true
+				}
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, e := range v {
+				if p, ok := findUnknownField(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); ok {
+					return p, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// jsonFields returns the types of the fields of given struct type indexed by their JSON name, including the
+// fields promoted from embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for n, et := range jsonFields(ft) {
+				if _, ok := fields[n]; !ok {
+					fields[n] = et
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField returns the type of the field with given JSON name, preferring an exact match over a
+// case-insensitive one like encoding/json does.
+func lookupField(fields map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if t, ok := fields[name]; ok {
+		return t, true
+	}
+	for n, t := range fields {
+		if strings.EqualFold(n, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
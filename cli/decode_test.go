@@ -0,0 +1,67 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+type embeddedRequest struct {
+	listKeysRequest
+	Labels map[string]struct {
+		Value string `json:"value"`
+	} `json:"labels"`
+}
+
+func TestUnknownFieldPath(t *testing.T) {
+	verifyType := reflect.TypeOf(&plugin.VerifySignatureRequest{})
+	tests := map[string]struct {
+		in   string
+		t    reflect.Type
+		path string
+	}{
+		"topLevel":        {in: `{"contractVersion":"1.0","foo":1}`, t: verifyType, path: "foo"},
+		"nested":          {in: `{"signature":{"criticalAttributes":{"contentType":"ct","foo":1}}}`, t: verifyType, path: "signature.criticalAttributes.foo"},
+		"interfaceValue":  {in: `{"signature":{"criticalAttributes":{"extendedAttributes":{"a":1}}}}`, t: verifyType},
+		"caseInsensitive": {in: `{"ContractVersion":"1.0","Signature":{"CriticalAttributes":{"contentType":"ct"}}}`, t: verifyType},
+		"embedded":        {in: `{"prefix":"key","foo":1}`, t: reflect.TypeOf(embeddedRequest{}), path: "foo"},
+		"mapValue":        {in: `{"labels":{"env":{"value":"prod","foo":1}}}`, t: reflect.TypeOf(embeddedRequest{}), path: "labels.env.foo"},
+		"slice":           {in: `[{"prefix":"key"},{"prefix":"key","foo":1}]`, t: reflect.TypeOf([]listKeysRequest{}), path: "[1].foo"},
+		"malformed":       {in: `{"foo":`, t: verifyType},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, ok := unknownFieldPath([]byte(test.in), test.t)
+			if ok != (test.path != "") || path != test.path {
+				t.Errorf("unknownFieldPath() expected path '%s' but got '%s' (found: %v)", test.path, path, ok)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRequestStrictUnknownFieldPath(t *testing.T) {
+	strictCli, _ := New(mock.NewPlugin(false), WithStrictDecoding(0))
+	in := "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"ct\",\"signingScheme\":\"ss\",\"foo\":\"bar\"}}}"
+	var request plugin.VerifySignatureRequest
+	err := strictCli.unmarshalRequest(strings.NewReader(in), &request)
+	expected := "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: unknown field \\\"signature.criticalAttributes.foo\\\"\"}"
+	if err == nil || err.Error() != expected {
+		t.Errorf("unmarshalRequest() expected error '%s' but got '%v'", expected, err)
+	}
+}
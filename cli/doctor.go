@@ -0,0 +1,202 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// doctorCommand is the name of the command diagnosing the plugin installation.
+const doctorCommand = "doctor"
+
+const (
+	checkPass = "pass"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorFlags contains the flags of the doctor command offered by completion scripts.
+var doctorFlags = completionCommandFlags{Command: doctorCommand, Flags: []completionFlag{
+	{Name: "key-id", Description: "Id of the key to describe"},
+	{Name: "output", Description: "Output format", Values: []string{versionOutputJSON, versionOutputText}},
+	{Name: "plugin-config", Description: "Plugin config as key=value", Repeatable: true},
+}}
+
+// doctorReport is the report of the doctor command.
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+	Passed bool          `json:"passed"`
+}
+
+// doctorCheck is the result of a single check of the doctor command.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// pluginConfigFlag is a repeatable key=value flag setting the plugin config.
+type pluginConfigFlag map[string]string
+
+func (f pluginConfigFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f pluginConfigFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value but got %q", value)
+	}
+	f[k] = v
+	return nil
+}
+
+// WithDoctor enables the doctor command, which diagnoses the plugin installation and prints a report of the
+// following checks:
+//   - metadata: the plugin metadata is valid when fetched with the given plugin config, and the plugin implements
+//     the declared capabilities.
+//   - executable: the executable name is plugin.BinaryPrefix followed by the plugin name.
+//   - health: the plugin is healthy, if it implements plugin.HealthChecker.
+//   - describe-key: the key whose id is given by the --key-id flag can be described, if any.
+//
+// The plugin config is set with --plugin-config key=value flags, and the report is printed as text or JSON
+// depending on the --output flag. The command exits with 1 if any check fails. Unlike the other commands, it runs
+// even if the plugin metadata can't be loaded, in which case the checks depending on the metadata are skipped.
+func WithDoctor() Option {
+	return func(c *CLI) {
+		c.doctor = true
+	}
+}
+
+// runDoctor runs the checks of the doctor command with given flags and prints the report to given writer.
+// It returns false if any check failed.
+func (c *CLI) runDoctor(ctx context.Context, executable string, args []string, w io.Writer) (bool, error) {
+	fs := flag.NewFlagSet(doctorCommand, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	keyID := fs.String("key-id", "", "")
+	output := fs.String("output", versionOutputText, "")
+	pluginConfig := pluginConfigFlag{}
+	fs.Var(pluginConfig, "plugin-config", "")
+	if err := fs.Parse(args); err != nil {
+		return false, fmt.Errorf("Invalid doctor flags: %v", err)
+	}
+	if fs.NArg() != 0 {
+		return false, fmt.Errorf("Invalid doctor arguments: %v", fs.Args())
+	}
+	if *output != versionOutputText && *output != versionOutputJSON {
+		return false, fmt.Errorf("Invalid doctor output %q, valid outputs are: <%s|%s>", *output, versionOutputJSON, versionOutputText)
+	}
+	if len(pluginConfig) == 0 {
+		pluginConfig = nil
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	report := doctorReport{Passed: true}
+	add := func(name string, status string, message string) {
+		report.Checks = append(report.Checks, doctorCheck{Name: name, Status: status, Message: message})
+		report.Passed = report.Passed && status != checkFail
+	}
+
+	md, err := c.fetchMetadata(ctx, pluginConfig)
+	if err != nil {
+		add("metadata", checkFail, errorMessage(err))
+		md = c.md
+	} else {
+		add("metadata", checkPass, fmt.Sprintf("%s %s", md.Name, md.Version))
+	}
+
+	if md == nil {
+		add("executable", checkSkip, "plugin metadata is unavailable")
+	} else if err := validateExecutableName(md, executable); err != nil {
+		add("executable", checkFail, err.Error())
+	} else {
+		add("executable", checkPass, plugin.BinaryPrefix+md.Name)
+	}
+
+	if hc, ok := c.pl.(plugin.HealthChecker); !ok {
+		add("health", checkSkip, "plugin doesn't implement plugin.HealthChecker")
+	} else if err := c.checkHealth(ctx, hc, pluginConfig); err != nil {
+		add("health", checkFail, errorMessage(err))
+	} else {
+		add("health", checkPass, "")
+	}
+
+	switch p, ok := c.pl.(plugin.SignatureGeneratorPlugin); {
+	case *keyID == "":
+		add(string(plugin.CommandDescribeKey), checkSkip, "no key id given, use --key-id to describe a key")
+	case md == nil:
+		add(string(plugin.CommandDescribeKey), checkSkip, "plugin metadata is unavailable")
+	case !ok || !md.HasCapability(plugin.CapabilitySignatureGenerator):
+		add(string(plugin.CommandDescribeKey), checkFail, fmt.Sprintf("plugin doesn't have capability %q", plugin.CapabilitySignatureGenerator))
+	default:
+		if resp, err := c.describeKey(ctx, p, *keyID, pluginConfig); err != nil {
+			add(string(plugin.CommandDescribeKey), checkFail, errorMessage(err))
+		} else {
+			add(string(plugin.CommandDescribeKey), checkPass, fmt.Sprintf("key %s has key spec %s", resp.KeyID, resp.KeySpec))
+		}
+	}
+
+	if *output == versionOutputJSON {
+		return report.Passed, json.NewEncoder(w).Encode(report)
+	}
+	for _, check := range report.Checks {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(check.Status), check.Name)
+		if check.Message != "" {
+			line += ": " + check.Message
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	return report.Passed, nil
+}
+
+// checkHealth calls CheckHealth of given plugin.HealthChecker, converting any panic into an error.
+func (c *CLI) checkHealth(ctx context.Context, hc plugin.HealthChecker, pluginConfig map[string]string) (err error) {
+	defer c.recoverPanic(&err)
+	return hc.CheckHealth(ctx, pluginConfig)
+}
+
+// describeKey describes the key with given id and validates the response, converting any panic into an error.
+func (c *CLI) describeKey(ctx context.Context, p plugin.SignatureGeneratorPlugin, keyID string, pluginConfig map[string]string) (resp *plugin.DescribeKeyResponse, err error) {
+	defer c.recoverPanic(&err)
+	req := &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: keyID, PluginConfig: pluginConfig}
+	resp, err = p.DescribeKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, resp.Validate(req)
+}
+
+// errorMessage returns the message of given error, without the JSON formatting of plugin errors.
+func errorMessage(err error) string {
+	var plErr *plugin.Error
+	if errors.As(err, &plErr) {
+		return plErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,245 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+type healthPlugin struct {
+	plugin.Plugin
+	pluginConfig map[string]string
+}
+
+func (p *healthPlugin) CheckHealth(_ context.Context, pluginConfig map[string]string) error {
+	p.pluginConfig = pluginConfig
+	if pluginConfig["vault"] == "unreachable" {
+		return plugin.NewGenericError("vault is unreachable")
+	}
+	return nil
+}
+
+func TestRunDoctor(t *testing.T) {
+	pl := &healthPlugin{Plugin: mock.NewSigGeneratorPlugin(false)}
+	c, err := New(pl, WithDoctor())
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	tests := map[string]struct {
+		executable string
+		args       []string
+		exitCode   int
+		stdout     string
+	}{
+		"success": {
+			args: []string{"--key-id", "someKeyId"},
+			stdout: "[PASS] metadata: com.example.plugin 1.0.0\n" +
+				"[PASS] executable: notation-com.example.plugin\n" +
+				"[PASS] health\n" +
+				"[PASS] describe-key: key someKeyId has key spec RSA-2048\n",
+		},
+		"noKeyID": {
+			stdout: "[PASS] metadata: com.example.plugin 1.0.0\n" +
+				"[PASS] executable: notation-com.example.plugin\n" +
+				"[PASS] health\n" +
+				"[SKIP] describe-key: no key id given, use --key-id to describe a key\n",
+		},
+		"unhealthy": {
+			args:     []string{"--plugin-config", "vault=unreachable", "--key-id", "otherKeyId"},
+			exitCode: 1,
+			stdout: "[PASS] metadata: com.example.plugin 1.0.0\n" +
+				"[PASS] executable: notation-com.example.plugin\n" +
+				"[FAIL] health: vault is unreachable\n" +
+				"[FAIL] describe-key: keyId \"someKeyId\" doesn't match the requested keyId \"otherKeyId\"\n",
+		},
+		"invalidExecutable": {
+			executable: "/home/user/.config/notation/plugins/com.example.plugin/notation-example",
+			exitCode:   1,
+			stdout: "[PASS] metadata: com.example.plugin 1.0.0\n" +
+				"[FAIL] executable: Invalid plugin executable name \"notation-example\", expected \"notation-com.example.plugin\"\n" +
+				"[PASS] health\n" +
+				"[SKIP] describe-key: no key id given, use --key-id to describe a key\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			executable := pluginExecutable
+			if test.executable != "" {
+				executable = test.executable
+			}
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), append([]string{executable, "doctor"}, test.args...), strings.NewReader(""), &stdout, &stderr); code != test.exitCode {
+				t.Errorf("Run() expected exit code %d but got %d, stderr: %s", test.exitCode, code, stderr.String())
+			}
+			if stdout.String() != test.stdout {
+				t.Errorf("Run() expected stdout '%s' but got '%s'", test.stdout, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunDoctorJSON(t *testing.T) {
+	pl := &healthPlugin{Plugin: mock.NewSigGeneratorPlugin(false)}
+	c, _ := New(pl, WithDoctor())
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, "doctor", "--output", "json", "--plugin-config", "region=eu", "--plugin-config", "vault=local"}
+	if code := c.Run(context.Background(), args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	var report doctorReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("failed to unmarshal doctor report: %v", err)
+	}
+	expected := doctorReport{
+		Passed: true,
+		Checks: []doctorCheck{
+			{Name: "metadata", Status: checkPass, Message: "com.example.plugin 1.0.0"},
+			{Name: "executable", Status: checkPass, Message: "notation-com.example.plugin"},
+			{Name: "health", Status: checkPass},
+			{Name: "describe-key", Status: checkSkip, Message: "no key id given, use --key-id to describe a key"},
+		},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Run() expected report %+v but got %+v", expected, report)
+	}
+	if config := map[string]string{"region": "eu", "vault": "local"}; !reflect.DeepEqual(pl.pluginConfig, config) {
+		t.Errorf("expected plugin config %v but got %v", config, pl.pluginConfig)
+	}
+}
+
+func TestRunDoctorWithoutHealthChecker(t *testing.T) {
+	c, _ := New(mock.NewPlugin(false), WithDoctor())
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), []string{pluginExecutable, "doctor", "--key-id", "someKeyId"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Run() expected exit code 1 but got %d", code)
+	}
+	expected := "[SKIP] health: plugin doesn't implement plugin.HealthChecker\n" +
+		"[FAIL] describe-key: plugin doesn't have capability \"SIGNATURE_GENERATOR.RAW\"\n"
+	if !strings.HasSuffix(stdout.String(), expected) {
+		t.Errorf("Run() expected stdout ending with '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestRunDoctorMetadataError(t *testing.T) {
+	tests := map[string]struct {
+		pl     plugin.GenericPlugin
+		stdout string
+		stderr string
+	}{
+		"error": {
+			pl: &healthPlugin{Plugin: mock.NewSigGeneratorPlugin(true)},
+			stdout: "[FAIL] metadata: GetMetadata() expected error\n" +
+				"[SKIP] executable: plugin metadata is unavailable\n" +
+				"[PASS] health\n" +
+				"[SKIP] describe-key: plugin metadata is unavailable\n",
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"GetMetadata() expected error\"}",
+		},
+		"invalidMetadata": {
+			pl: &metadataPlugin{md: &plugin.GetMetadataResponse{
+				Name:                      "com.example.plugin",
+				Version:                   "v1",
+				URL:                       "https://example.com/notation/plugin",
+				SupportedContractVersions: []string{plugin.ContractVersion},
+				Capabilities:              []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier},
+			}},
+			stdout: "[FAIL] metadata: invalid plugin metadata: version \"v1\" is not a valid semantic version\n" +
+				"[SKIP] executable: plugin metadata is unavailable\n" +
+				"[SKIP] health: plugin doesn't implement plugin.HealthChecker\n" +
+				"[SKIP] describe-key: plugin metadata is unavailable\n",
+			stderr: "{\"errorCode\":\"ERROR\",\"errorMessage\":\"invalid plugin metadata: version \\\"v1\\\" is not a valid semantic version\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New(test.pl, WithDoctor())
+			if err != nil {
+				t.Fatalf("New() failed with error: %v", err)
+			}
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), []string{pluginExecutable, "doctor", "--key-id", "someKeyId"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+				t.Errorf("Run() expected exit code 1 but got %d, stderr: %s", code, stderr.String())
+			}
+			if stdout.String() != test.stdout {
+				t.Errorf("Run() expected stdout '%s' but got '%s'", test.stdout, stdout.String())
+			}
+
+			// the other commands fail with the metadata error
+			stdout.Reset()
+			if code := c.Run(context.Background(), []string{pluginExecutable, string(plugin.Version)}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+				t.Errorf("Run() expected exit code 1 but got %d", code)
+			}
+			if stdout.String() != "" || stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got stdout '%s' and stderr '%s'", test.stderr, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunDoctorError(t *testing.T) {
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithDoctor())
+	tests := map[string]struct {
+		args   []string
+		stderr string
+	}{
+		"unknownFlag":   {args: []string{"--verbose"}, stderr: "Invalid doctor flags: flag provided but not defined: -verbose"},
+		"invalidConfig": {args: []string{"--plugin-config", "vault"}, stderr: "Invalid doctor flags: invalid value \"vault\" for flag -plugin-config: expected key=value but got \"vault\""},
+		"extraArgs":     {args: []string{"check"}, stderr: "Invalid doctor arguments: [check]"},
+		"invalidOutput": {args: []string{"--output", "yaml"}, stderr: "Invalid doctor output \"yaml\", valid outputs are: <json|text>"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := c.Run(context.Background(), append([]string{pluginExecutable, "doctor"}, test.args...), strings.NewReader(""), &stdout, &stderr); code != 1 {
+				t.Errorf("Run() expected exit code 1 but got %d", code)
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunDoctorDisabled(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := cli.Run(context.Background(), []string{pluginExecutable, "doctor"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Run() expected exit code 1 but got %d", code)
+	}
+	if !strings.HasPrefix(stderr.String(), "Invalid command, valid commands are:") {
+		t.Errorf("Run() expected invalid command error but got '%s'", stderr.String())
+	}
+}
+
+func TestNewDoctorCommandReserved(t *testing.T) {
+	if _, err := New(mock.NewSigGeneratorPlugin(false), WithCommand("doctor", "description", listKeys)); err == nil {
+		t.Error("New() expected error for reserved command name doctor")
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	if msg := errorMessage(plugin.NewGenericError("failed")); msg != "failed" {
+		t.Errorf("errorMessage() expected 'failed' but got '%s'", msg)
+	}
+	if msg := errorMessage(errors.New("failed")); msg != "failed" {
+		t.Errorf("errorMessage() expected 'failed' but got '%s'", msg)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// frameworkModulePath is the module path of this framework, used to look up its version in the build info.
+const frameworkModulePath = "github.com/notaryproject/notation-plugin-framework-go"
+
+const (
+	versionOutputText = "text"
+	versionOutputJSON = "json"
+)
+
+// minCommandWidth is the minimum width of the command names in the help output.
+const minCommandWidth = 21
+
+// commandHelp contains the one-line description and the expected stdin JSON shape of a command.
+type commandHelp struct {
+	description string
+	stdin       string
+}
+
+// commandHelps contains the help of every command supported by the CLI.
+var commandHelps = map[string]commandHelp{
+	string(plugin.CommandGetMetadata): {
+		description: "Returns the plugin metadata",
+		stdin:       `{"pluginConfig":{"<key>":"<value>"}}`,
+	},
+	string(plugin.CommandDescribeKey): {
+		description: "Returns the key spec of the given key",
+		stdin:       `{"contractVersion":"1.0","keyId":"<key id>","pluginConfig":{"<key>":"<value>"}}`,
+	},
+	string(plugin.CommandGenerateSignature): {
+		description: "Signs the payload using the given key and returns the raw signature",
+		stdin:       `{"contractVersion":"1.0","keyId":"<key id>","keySpec":"<key spec>","hashAlgorithm":"<hash algorithm>","payload":"<base64>","pluginConfig":{"<key>":"<value>"}}`,
+	},
+	string(plugin.CommandGenerateEnvelope): {
+		description: "Signs the payload using the given key and returns the signature envelope",
+		stdin:       `{"contractVersion":"1.0","keyId":"<key id>","payloadType":"<media type>","signatureEnvelopeType":"<media type>","payload":"<base64>","expiryDurationInSeconds":0,"pluginConfig":{"<key>":"<value>"}}`,
+	},
+	string(plugin.CommandVerifySignature): {
+		description: "Verifies the signature for the requested verification capabilities",
+		stdin:       `{"contractVersion":"1.0","signature":{"criticalAttributes":{"contentType":"<media type>","signingScheme":"<signing scheme>"},"unprocessedAttributes":[],"certificateChain":["<base64>"]},"trustPolicy":{"trustedIdentities":[],"signatureVerification":["<capability>"]},"pluginConfig":{"<key>":"<value>"}}`,
+	},
+	string(plugin.CommandGenerateSignatureBatch): {
+		description: "Signs a batch of generate-signature requests and returns the result of each request",
+		stdin:       `[<generate-signature stdin>, ...] or one generate-signature stdin per line`,
+	},
+	doctorCommand: {
+		description: "Diagnoses the plugin installation, use \"--key-id <key id>\" to describe a key and \"--output json\" for machine-readable output",
+	},
+	string(plugin.Version): {
+		description: "Prints the plugin version, use \"--output json\" for machine-readable output",
+	},
+}
+
+// versionInfo is the machine-readable output of the version command.
+type versionInfo struct {
+	*plugin.GetMetadataResponse
+	FrameworkVersion string     `json:"frameworkVersion"`
+	Build            *buildInfo `json:"build,omitempty"`
+}
+
+// buildInfo is the Go build information of the plugin executable.
+type buildInfo struct {
+	GoVersion string            `json:"goVersion"`
+	Path      string            `json:"path"`
+	Version   string            `json:"version"`
+	Settings  map[string]string `json:"settings,omitempty"`
+}
+
+// isHelpArgs returns true if the given arguments ask for help, i.e. no command or one of the help flags.
+func isHelpArgs(args []string) bool {
+	if len(args) < 2 {
+		return true
+	}
+	switch args[1] {
+	case "help", "--help", "-h":
+		return len(args) == 2
+	}
+	return false
+}
+
+// printHelp prints the usage of the executable along with the description and the expected input of every
+// command supported by the plugin, followed by the vendor commands.
+func (c *CLI) printHelp(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "%s - %s\n\nUsage:\n  %s%s <command>\n\nCommands:\n", c.md.Name, c.md.Description, plugin.BinaryPrefix, c.md.Name)
+	width := minCommandWidth
+	for _, arg := range c.validArgs() {
+		if len(arg) > width {
+			width = len(arg)
+		}
+	}
+	c.printCommandHelps(w, getValidArgs(c.md), width)
+	if vendorCommands := c.vendorCommands(); len(vendorCommands) > 0 {
+		_, _ = fmt.Fprint(w, "\nVendor Commands:\n")
+		c.printCommandHelps(w, vendorCommands, width)
+	}
+}
+
+// printCommandHelps prints the description and the expected input of given commands, padding command names to
+// given width.
+func (c *CLI) printCommandHelps(w io.Writer, commands []string, width int) {
+	for _, arg := range commands {
+		h := commandHelps[arg]
+		if cmd, ok := c.customCommand(plugin.Command(arg)); ok {
+			h = commandHelp{description: cmd.description}
+		}
+		_, _ = fmt.Fprintf(w, "  %-*s %s\n", width, arg, h.description)
+		if h.stdin != "" {
+			_, _ = fmt.Fprintf(w, "  %-*s stdin: %s\n", width, "", h.stdin)
+		}
+	}
+}
+
+// parseVersionOutput parses the flags of the version command and returns the requested output format.
+func parseVersionOutput(args []string) (string, error) {
+	fs := flag.NewFlagSet(string(plugin.Version), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	output := fs.String("output", versionOutputText, "")
+	if err := fs.Parse(args); err != nil {
+		return "", fmt.Errorf("Invalid version flags: %v", err)
+	}
+	if fs.NArg() != 0 {
+		return "", fmt.Errorf("Invalid version arguments: %v", fs.Args())
+	}
+	if *output != versionOutputText && *output != versionOutputJSON {
+		return "", fmt.Errorf("Invalid version output %q, valid outputs are: <%s|%s>", *output, versionOutputJSON, versionOutputText)
+	}
+	return *output, nil
+}
+
+// printVersionJSON prints the plugin metadata along with the framework version and the Go build information.
+func printVersionJSON(md *plugin.GetMetadataResponse, w io.Writer) error {
+	info := versionInfo{
+		GetMetadataResponse: md,
+		FrameworkVersion:    "unknown",
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.FrameworkVersion = frameworkVersion(bi)
+		info.Build = &buildInfo{
+			GoVersion: bi.GoVersion,
+			Path:      bi.Path,
+			Version:   bi.Main.Version,
+		}
+		if len(bi.Settings) > 0 {
+			info.Build.Settings = make(map[string]string, len(bi.Settings))
+			for _, s := range bi.Settings {
+				info.Build.Settings[s.Key] = s.Value
+			}
+		}
+	}
+
+	out, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(w, string(out))
+	return nil
+}
+
+// frameworkVersion returns the version of this framework the executable was built with.
+func frameworkVersion(bi *debug.BuildInfo) string {
+	if bi.Main.Path == frameworkModulePath {
+		return bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == frameworkModulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
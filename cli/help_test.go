@@ -0,0 +1,179 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestRunHelp(t *testing.T) {
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false))
+	expected := "com.example.plugin - This is an description of example plugin. 🍺\n\n" +
+		"Usage:\n  notation-com.example.plugin <command>\n\n" +
+		"Commands:\n" +
+		"  describe-key          Returns the key spec of the given key\n" +
+		"                        stdin: " + commandHelps[string(plugin.CommandDescribeKey)].stdin + "\n" +
+		"  generate-signature    Signs the payload using the given key and returns the raw signature\n" +
+		"                        stdin: " + commandHelps[string(plugin.CommandGenerateSignature)].stdin + "\n" +
+		"  get-plugin-metadata   Returns the plugin metadata\n" +
+		"                        stdin: " + commandHelps[string(plugin.CommandGetMetadata)].stdin + "\n" +
+		"  verify-signature      Verifies the signature for the requested verification capabilities\n" +
+		"                        stdin: " + commandHelps[string(plugin.CommandVerifySignature)].stdin + "\n" +
+		"  version               Prints the plugin version, use \"--output json\" for machine-readable output\n"
+
+	for _, args := range [][]string{{pluginExecutable}, {pluginExecutable, "help"}, {pluginExecutable, "--help"}, {pluginExecutable, "-h"}} {
+		t.Run(strings.Join(args[1:], ""), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := sigGenCli.Run(context.Background(), args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+				t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+			}
+			if stdout.String() != expected {
+				t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestCommandHelps(t *testing.T) {
+	md := &plugin.GetMetadataResponse{Capabilities: []plugin.Capability{
+		plugin.CapabilitySignatureGenerator,
+		plugin.CapabilityEnvelopeGenerator,
+		plugin.CapabilityTrustedIdentityVerifier,
+	}}
+	for _, arg := range getValidArgs(md) {
+		if commandHelps[arg].description == "" {
+			t.Errorf("command %s doesn't have a description", arg)
+		}
+	}
+}
+
+func TestRunVersionJSON(t *testing.T) {
+	for _, args := range [][]string{{"--output", "json"}, {"--output=json"}, {"-output", "json"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := cli.Run(context.Background(), append([]string{pluginExecutable, string(plugin.Version)}, args...), strings.NewReader(""), &stdout, &stderr)
+			if code != 0 {
+				t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+			}
+
+			var info struct {
+				plugin.GetMetadataResponse
+				FrameworkVersion string `json:"frameworkVersion"`
+				Build            struct {
+					GoVersion string `json:"goVersion"`
+				} `json:"build"`
+			}
+			if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+				t.Fatalf("Run() printed invalid JSON '%s': %v", stdout.String(), err)
+			}
+			md, _ := mock.NewPlugin(false).GetMetadata(context.Background(), &plugin.GetMetadataRequest{})
+			if !reflect.DeepEqual(info.GetMetadataResponse, *md) {
+				t.Errorf("Run() expected metadata %+v but got %+v", *md, info.GetMetadataResponse)
+			}
+			if info.FrameworkVersion == "" {
+				t.Error("Run() expected frameworkVersion to be set")
+			}
+			if info.Build.GoVersion == "" {
+				t.Error("Run() expected build goVersion to be set")
+			}
+		})
+	}
+}
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"default": {args: nil, expected: versionOutputText},
+		"text":    {args: []string{"--output", "text"}, expected: versionOutputText},
+		"json":    {args: []string{"--output", "json"}, expected: versionOutputJSON},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := parseVersionOutput(test.args)
+			if err != nil {
+				t.Fatalf("parseVersionOutput() returned error: %v", err)
+			}
+			if output != test.expected {
+				t.Errorf("parseVersionOutput() expected '%s' but got '%s'", test.expected, output)
+			}
+		})
+	}
+}
+
+func TestParseVersionOutputError(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"unknownFlag":   {args: []string{"--format", "json"}, expected: "Invalid version flags: flag provided but not defined: -format"},
+		"missingValue":  {args: []string{"--output"}, expected: "Invalid version flags: flag needs an argument: -output"},
+		"extraArgs":     {args: []string{"--output", "json", "extra"}, expected: "Invalid version arguments: [extra]"},
+		"unknownOutput": {args: []string{"--output", "yaml"}, expected: "Invalid version output \"yaml\", valid outputs are: <json|text>"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseVersionOutput(test.args); err == nil || err.Error() != test.expected {
+				t.Errorf("parseVersionOutput() expected error '%s' but got '%v'", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestFrameworkVersion(t *testing.T) {
+	tests := map[string]struct {
+		bi       *debug.BuildInfo
+		expected string
+	}{
+		"main": {
+			bi:       &debug.BuildInfo{Main: debug.Module{Path: frameworkModulePath, Version: "(devel)"}},
+			expected: "(devel)",
+		},
+		"dependency": {
+			bi: &debug.BuildInfo{
+				Main: debug.Module{Path: "github.com/example/plugin"},
+				Deps: []*debug.Module{{Path: frameworkModulePath, Version: "v1.0.0"}},
+			},
+			expected: "v1.0.0",
+		},
+		"replaced": {
+			bi: &debug.BuildInfo{
+				Main: debug.Module{Path: "github.com/example/plugin"},
+				Deps: []*debug.Module{{Path: frameworkModulePath, Version: "v1.0.0", Replace: &debug.Module{Path: "github.com/example/fork", Version: "v1.0.1"}}},
+			},
+			expected: "v1.0.1",
+		},
+		"missing": {
+			bi:       &debug.BuildInfo{Main: debug.Module{Path: "github.com/example/plugin"}},
+			expected: "unknown",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if v := frameworkVersion(test.bi); v != test.expected {
+				t.Errorf("frameworkVersion() expected '%s' but got '%s'", test.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// httpStatusCodes maps plugin error codes to HTTP status codes. Other error codes map to
+// http.StatusInternalServerError.
+var httpStatusCodes = map[plugin.ErrorCode]int{
+	plugin.ErrorCodeValidation:                 http.StatusBadRequest,
+	plugin.ErrorCodeUnsupportedContractVersion: http.StatusBadRequest,
+	plugin.ErrorCodeAccessDenied:               http.StatusForbidden,
+	plugin.ErrorCodeThrottled:                  http.StatusTooManyRequests,
+	plugin.ErrorCodeTimeout:                    http.StatusGatewayTimeout,
+}
+
+// HTTPHandler returns an http.Handler exposing the plugin commands as POST endpoints, e.g. "/describe-key".
+// The request body is the JSON the command reads from stdin, and it goes through the same validation as the CLI.
+// The response body is the JSON the command writes to stdout, or the plugin.Error it writes to stderr with an HTTP
+// status code derived from its error code: 400 for VALIDATION_ERROR and UNSUPPORTED_CONTRACT_VERSION, 403 for
+// ACCESS_DENIED, 429 for THROTTLED, 504 for TIMEOUT and 500 otherwise. Requests fail with the error of the plugin
+// metadata if it can't be loaded. Plugin calls that don't return once timed out are abandoned, see WithTimeout.
+func (c *CLI) HTTPHandler() http.Handler {
+	return http.HandlerFunc(c.serveHTTP)
+}
+
+func (c *CLI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := c.loadMetadata(r.Context()); err != nil {
+		writeHTTPError(w, httpStatusCode(err), err)
+		return
+	}
+	command := strings.TrimPrefix(r.URL.Path, "/")
+	if !c.isPluginCommand(command) {
+		writeHTTPError(w, http.StatusNotFound, plugin.NewGenericErrorf("command %q not found", command))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeHTTPError(w, http.StatusMethodNotAllowed, plugin.NewGenericErrorf("method %s is not allowed, use %s", r.Method, http.MethodPost))
+		return
+	}
+
+	ctx, cancel := c.withTimeout(r.Context())
+	defer cancel()
+	op, pluginErr := c.execRequest(ctx, plugin.Command(command), r.Body)
+	if pluginErr != nil {
+		writeHTTPError(w, httpStatusCode(pluginErr), pluginErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, op)
+}
+
+// httpStatusCode returns the HTTP status code derived from the error code of given plugin error.
+func httpStatusCode(err *plugin.Error) int {
+	if status, ok := httpStatusCodes[err.ErrCode]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// writeHTTPError writes given plugin error as JSON with given HTTP status code.
+func writeHTTPError(w http.ResponseWriter, status int, err *plugin.Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprint(w, err.Error())
+}
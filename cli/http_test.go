@@ -0,0 +1,177 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	sigGenCli, _ := New(mock.NewSigGeneratorPlugin(false))
+	failingCli, _ := New(mock.NewSigGeneratorPlugin(true), WithDoctor())
+	errorCode := func(code plugin.ErrorCode) Interceptor {
+		return func(_ context.Context, _ plugin.Command, _ plugin.Request, _ Handler) (any, error) {
+			return nil, plugin.NewError(code, "failed")
+		}
+	}
+	tests := map[string]struct {
+		c      *CLI
+		method string
+		path   string
+		body   string
+		status int
+		resp   string
+	}{
+		"success": {
+			c:      sigGenCli,
+			method: http.MethodPost,
+			path:   "/describe-key",
+			body:   "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}",
+			status: http.StatusOK,
+			resp:   "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\"}",
+		},
+		"validationError": {
+			c:      sigGenCli,
+			method: http.MethodPost,
+			path:   "/describe-key",
+			body:   "{\"keyId\":\"someKeyId\"}",
+			status: http.StatusBadRequest,
+			resp:   "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: contractVersion cannot be empty\"}",
+		},
+		"unsupportedContractVersion": {
+			c:      sigGenCli,
+			method: http.MethodPost,
+			path:   "/describe-key",
+			body:   "{\"contractVersion\":\"2.0\",\"keyId\":\"someKeyId\"}",
+			status: http.StatusBadRequest,
+			resp:   "{\"errorCode\":\"UNSUPPORTED_CONTRACT_VERSION\",\"errorMessage\":\"\\\"2.0\\\" is not a supported notary plugin contract version\"}",
+		},
+		"unknownCommand": {
+			c:      sigGenCli,
+			method: http.MethodPost,
+			path:   "/generate-envelope",
+			status: http.StatusNotFound,
+			resp:   "{\"errorCode\":\"ERROR\",\"errorMessage\":\"command \\\"generate-envelope\\\" not found\"}",
+		},
+		"version": {
+			c:      sigGenCli,
+			method: http.MethodPost,
+			path:   "/version",
+			status: http.StatusNotFound,
+			resp:   "{\"errorCode\":\"ERROR\",\"errorMessage\":\"command \\\"version\\\" not found\"}",
+		},
+		"metadataError": {
+			c:      failingCli,
+			method: http.MethodPost,
+			path:   "/describe-key",
+			body:   "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}",
+			status: http.StatusInternalServerError,
+			resp:   "{\"errorCode\":\"ERROR\",\"errorMessage\":\"GetMetadata() expected error\"}",
+		},
+		"methodNotAllowed": {
+			c:      sigGenCli,
+			method: http.MethodGet,
+			path:   "/describe-key",
+			status: http.StatusMethodNotAllowed,
+			resp:   "{\"errorCode\":\"ERROR\",\"errorMessage\":\"method GET is not allowed, use POST\"}",
+		},
+	}
+	statuses := map[plugin.ErrorCode]int{
+		plugin.ErrorCodeAccessDenied: http.StatusForbidden,
+		plugin.ErrorCodeThrottled:    http.StatusTooManyRequests,
+		plugin.ErrorCodeTimeout:      http.StatusGatewayTimeout,
+		plugin.ErrorCodeGeneric:      http.StatusInternalServerError,
+	}
+	for code, status := range statuses {
+		c, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(errorCode(code)))
+		tests[string(code)] = struct {
+			c      *CLI
+			method string
+			path   string
+			body   string
+			status int
+			resp   string
+		}{
+			c:      c,
+			method: http.MethodPost,
+			path:   "/describe-key",
+			body:   "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}",
+			status: status,
+			resp:   "{\"errorCode\":\"" + string(code) + "\",\"errorMessage\":\"failed\"}",
+		}
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			test.c.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			if rec.Code != test.status {
+				t.Errorf("expected status %d but got %d", test.status, rec.Code)
+			}
+			if rec.Body.String() != test.resp {
+				t.Errorf("expected body '%s' but got '%s'", test.resp, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected Content-Type application/json but got '%s'", ct)
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerMetadataRetry(t *testing.T) {
+	pl := &flakyMetadataPlugin{Plugin: mock.NewSigGeneratorPlugin(false), failures: 2}
+	c, err := New(pl, WithDoctor(), WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	statuses := []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK}
+	for i, status := range statuses {
+		rec := httptest.NewRecorder()
+		c.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/describe-key", strings.NewReader("{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}")))
+		if rec.Code != status {
+			t.Errorf("request %d: expected status %d but got %d, body: %s", i, status, rec.Code, rec.Body.String())
+		}
+	}
+	if pl.calls != 3 {
+		t.Errorf("expected 3 GetMetadata calls but got %d", pl.calls)
+	}
+	if !pl.deadline {
+		t.Error("expected GetMetadata to be called within the configured timeout")
+	}
+}
+
+// flakyMetadataPlugin wraps a plugin and fails the first given number of GetMetadata calls.
+type flakyMetadataPlugin struct {
+	plugin.Plugin
+	failures int
+	calls    int
+	deadline bool
+}
+
+func (p *flakyMetadataPlugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	p.calls++
+	_, p.deadline = ctx.Deadline()
+	if p.calls <= p.failures {
+		return nil, plugin.NewGenericError("backend temporarily unavailable")
+	}
+	return p.Plugin.GetMetadata(ctx, req)
+}
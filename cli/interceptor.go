@@ -0,0 +1,60 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Handler executes a plugin command for the given request and returns its response.
+type Handler func(ctx context.Context, req plugin.Request) (any, error)
+
+// Interceptor intercepts the execution of a plugin command, after the request has been read and validated.
+// It receives the command, the decoded request and the next handler in the chain, and is responsible for calling
+// next. An interceptor may inspect or replace the request passed to next as well as the response or error
+// returned by it, which makes it suitable for cross-cutting concerns such as auditing, metrics or
+// additional input checks. The returned response is validated against the request the plugin received.
+type Interceptor func(ctx context.Context, command plugin.Command, req plugin.Request, next Handler) (any, error)
+
+// WithInterceptors adds interceptors around the execution of plugin commands. Interceptors are invoked in the
+// given order, i.e. the first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *CLI) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// chain wraps given handler with the interceptors configured on the CLI.
+func (c *CLI) chain(command plugin.Command, h Handler) Handler {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.interceptors[i], h
+		h = func(ctx context.Context, req plugin.Request) (any, error) {
+			return interceptor(ctx, command, req, next)
+		}
+	}
+	return h
+}
+
+// handlerFor adapts a plugin function into a Handler.
+func handlerFor[T plugin.Request, R any](f func(context.Context, T) (R, error)) Handler {
+	return func(ctx context.Context, req plugin.Request) (any, error) {
+		r, ok := req.(T)
+		if !ok {
+			return nil, plugin.NewGenericErrorf("unexpected request type %T", req)
+		}
+		return f(ctx, r)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestWithInterceptors(t *testing.T) {
+	var calls []string
+	recorder := func(name string) Interceptor {
+		return func(ctx context.Context, command plugin.Command, req plugin.Request, next Handler) (any, error) {
+			calls = append(calls, name+":"+string(command))
+			return next(ctx, req)
+		}
+	}
+	replaceKeySpec := func(ctx context.Context, command plugin.Command, req plugin.Request, next Handler) (any, error) {
+		resp, err := next(ctx, req)
+		if r, ok := resp.(*plugin.DescribeKeyResponse); ok {
+			r.KeySpec = plugin.KeySpecRSA3072
+		}
+		return resp, err
+	}
+
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(recorder("first"), replaceKeySpec), WithInterceptors(recorder("second")))
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"
+	if code := c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+
+	expectedCalls := []string{"first:describe-key", "second:describe-key"}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("expected interceptor calls %v but got %v", expectedCalls, calls)
+	}
+	expected := "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-3072\"}"
+	if stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestWithInterceptorsReplaceRequest(t *testing.T) {
+	resolveAlias := func(ctx context.Context, _ plugin.Command, req plugin.Request, next Handler) (any, error) {
+		if r, ok := req.(*plugin.DescribeKeyRequest); ok && r.KeyID == "alias/signing" {
+			resolved := *r
+			resolved.KeyID = "someKeyId"
+			return next(ctx, &resolved)
+		}
+		return next(ctx, req)
+	}
+
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(resolveAlias))
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"alias/signing\"}"
+	if code := c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	expected := "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\"}"
+	if stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+func TestWithInterceptorsError(t *testing.T) {
+	deny := func(_ context.Context, _ plugin.Command, _ plugin.Request, _ Handler) (any, error) {
+		return nil, plugin.NewError(plugin.ErrorCodeAccessDenied, "token expired")
+	}
+
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(deny))
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	in := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"
+	if code := c.Run(context.Background(), args, strings.NewReader(in), &stdout, &stderr); code == 0 {
+		t.Errorf("Run() expected nonzero exit code")
+	}
+
+	expected := "{\"errorCode\":\"ACCESS_DENIED\",\"errorMessage\":\"token expired\"}"
+	if stderr.String() != expected {
+		t.Errorf("Run() expected stderr '%s' but got '%s'", expected, stderr.String())
+	}
+}
+
+func TestHandlerForUnexpectedRequest(t *testing.T) {
+	h := handlerFor(mock.NewSigGeneratorPlugin(false).DescribeKey)
+	_, err := h(context.Background(), &plugin.GetMetadataRequest{})
+	assertErr(t, err, plugin.ErrorCodeGeneric)
+}
@@ -0,0 +1,111 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxStrayOutput is the maximum number of bytes of stray output forwarded to the logger per stream. The remaining
+// stray output is discarded.
+const maxStrayOutput = 64 * 1024
+
+// strayOutputTimeout is the maximum duration to wait for the stray output of a stream once it's restored, e.g. when
+// a child process still holds the stream.
+const strayOutputTimeout = time.Second
+
+// strayOutput captures the output written to an isolated stream.
+type strayOutput struct {
+	stream string
+	r      *os.File
+	buf    bytes.Buffer
+	n      int
+	done   chan struct{}
+}
+
+// captureStrayOutput starts capturing the stray output of given stream from given pipe reader.
+func captureStrayOutput(stream string, r *os.File) *strayOutput {
+	s := &strayOutput{stream: stream, r: r, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		_, _ = io.Copy(s, r)
+	}()
+	return s
+}
+
+// Write keeps up to maxStrayOutput bytes of stray output and counts the rest.
+func (s *strayOutput) Write(p []byte) (int, error) {
+	if room := maxStrayOutput - s.buf.Len(); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		s.buf.Write(p[:room])
+	}
+	s.n += len(p)
+	return len(p), nil
+}
+
+// logStrayOutput waits for the stray output of given restored streams and forwards it to the logger at warn level.
+func (c *CLI) logStrayOutput(outputs ...*strayOutput) {
+	timeout := time.After(strayOutputTimeout)
+	for _, s := range outputs {
+		select {
+		case <-s.done:
+		case <-timeout:
+			c.logger.Warnf("%s is still in use after the plugin command, ignoring its remaining stray output", s.stream)
+		}
+		_ = s.r.Close()
+		<-s.done
+
+		for _, line := range strings.Split(strings.TrimRight(s.buf.String(), "\n"), "\n") {
+			if line != "" {
+				c.logger.Warnf("stray output written to %s: %s", s.stream, line)
+			}
+		}
+		if discarded := s.n - s.buf.Len(); discarded > 0 {
+			c.logger.Warnf("discarded %d bytes of stray output written to %s", discarded, s.stream)
+		}
+	}
+}
+
+// swapOutput replaces os.Stdout and os.Stderr with pipes until the returned function is called, so that nothing
+// written through them interferes with notation <-> plugin communication. The stray output is forwarded to the
+// logger once they are restored. It returns the original os.Stdout and os.Stderr.
+func (c *CLI) swapOutput() (stdout, stderr io.Writer, restore func()) {
+	origStdout, origStderr := os.Stdout, os.Stderr
+	var outputs []*strayOutput
+	pipe := func(stream string) *os.File {
+		r, w, err := os.Pipe()
+		if err != nil {
+			c.logger.Warnf("failed to capture %s, discarding its stray output: %v", stream, err)
+			// Ignoring error because we don't want plugin to fail if `os.DevNull` is misconfigured.
+			null, _ := os.Open(os.DevNull)
+			return null
+		}
+		outputs = append(outputs, captureStrayOutput(stream, r))
+		return w
+	}
+	os.Stdout, os.Stderr = pipe("stdout"), pipe("stderr")
+
+	return origStdout, origStderr, func() {
+		_ = os.Stdout.Close()
+		_ = os.Stderr.Close()
+		os.Stdout, os.Stderr = origStdout, origStderr
+		c.logStrayOutput(outputs...)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// isolateOutput makes sure that nothing gets emitted to stdout and stderr until the returned function is called,
+// so that the plugin or its dependencies don't interfere with notation <-> plugin communication.
+// The file descriptors of os.Stdout and os.Stderr are redirected to pipes, which isolates the output written by cgo
+// libraries, child processes and code holding os.Stdout or os.Stderr as well. The stray output is forwarded to the
+// logger once the file descriptors are restored. If the file descriptors can't be redirected, it falls back to
+// swapOutput. It returns writers to the original standard output and error.
+func (c *CLI) isolateOutput() (stdout, stderr io.Writer, restore func()) {
+	stdoutFile, stdoutOutput, restoreStdout, err := isolateFd(os.Stdout, "stdout")
+	if err != nil {
+		c.logger.Warnf("failed to isolate stdout file descriptor: %v", err)
+		return c.swapOutput()
+	}
+	stderrFile, stderrOutput, restoreStderr, err := isolateFd(os.Stderr, "stderr")
+	if err != nil {
+		restoreStdout()
+		c.logStrayOutput(stdoutOutput)
+		c.logger.Warnf("failed to isolate stderr file descriptor: %v", err)
+		return c.swapOutput()
+	}
+
+	return stdoutFile, stderrFile, func() {
+		restoreStdout()
+		restoreStderr()
+		c.logStrayOutput(stdoutOutput, stderrOutput)
+	}
+}
+
+// isolateFd redirects the file descriptor of given file to a pipe capturing its stray output until the returned
+// function is called. It returns a file writing to the original file descriptor, which is closed once restored.
+func isolateFd(f *os.File, stream string) (*os.File, *strayOutput, func(), error) {
+	fd := int(f.Fd())
+	saved, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to duplicate %s: %w", stream, err)
+	}
+	syscall.CloseOnExec(saved)
+	orig := os.NewFile(uintptr(saved), f.Name())
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		_ = orig.Close()
+		return nil, nil, nil, fmt.Errorf("failed to create %s pipe: %w", stream, err)
+	}
+	err = syscall.Dup3(int(w.Fd()), fd, 0)
+	_ = w.Close()
+	if err != nil {
+		_ = r.Close()
+		_ = orig.Close()
+		return nil, nil, nil, fmt.Errorf("failed to redirect %s: %w", stream, err)
+	}
+
+	return orig, captureStrayOutput(stream, r), func() {
+		// Ignoring error because the original file descriptor is known to be valid, and there is nowhere to report
+		// it anyway.
+		_ = syscall.Dup3(saved, fd, 0)
+		_ = orig.Close()
+	}, nil
+}
@@ -0,0 +1,42 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+)
+
+func TestIsolateOutput(t *testing.T) {
+	l := &warnLogger{}
+	c, _ := NewWithLogger(mock.NewPlugin(false), l)
+	assertIsolated(t, l, c.isolateOutput, func() {
+		_, _ = syscall.Write(int(os.Stdout.Fd()), []byte("fd output\n"))
+		cmd := exec.Command("sh", "-c", "echo child stdout; echo child stderr >&2")
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			t.Errorf("failed to run child process: %v", err)
+		}
+	}, []string{
+		"stray output written to stdout: fd output",
+		"stray output written to stdout: child stdout",
+		"stray output written to stderr: child stderr",
+	})
+}
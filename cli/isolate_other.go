@@ -0,0 +1,28 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package cli
+
+import (
+	"io"
+)
+
+// isolateOutput makes sure that nothing gets emitted to stdout and stderr until the returned function is called,
+// so that the plugin or its dependencies don't interfere with notation <-> plugin communication. Only the output
+// written through os.Stdout and os.Stderr is isolated, see swapOutput. It returns writers to the original standard
+// output and error.
+func (c *CLI) isolateOutput() (stdout, stderr io.Writer, restore func()) {
+	return c.swapOutput()
+}
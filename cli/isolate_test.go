@@ -0,0 +1,98 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+)
+
+func TestSwapOutput(t *testing.T) {
+	l := &warnLogger{}
+	c, _ := NewWithLogger(mock.NewPlugin(false), l)
+	assertIsolated(t, l, c.swapOutput, func() {
+		fmt.Print("stray stdout\n")
+		fmt.Fprint(os.Stderr, "stray stderr")
+	}, []string{"stray output written to stdout: stray stdout", "stray output written to stderr: stray stderr"})
+}
+
+func TestStrayOutputLimit(t *testing.T) {
+	l := &warnLogger{}
+	c, _ := NewWithLogger(mock.NewPlugin(false), l)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	s := captureStrayOutput("stdout", r)
+	_, _ = w.WriteString(strings.Repeat("a", maxStrayOutput) + "bcd")
+	_ = w.Close()
+	c.logStrayOutput(s)
+
+	expected := []string{"stray output written to stdout: " + strings.Repeat("a", maxStrayOutput), "discarded 3 bytes of stray output written to stdout"}
+	if !reflect.DeepEqual(l.warnings, expected) {
+		t.Errorf("expected warnings of %d and %d characters but got %q", len(expected[0]), len(expected[1]), l.warnings)
+	}
+}
+
+// assertIsolated checks that the output written by write while isolated by isolate is logged as expected, and that
+// the output written to the writers returned by isolate reaches the original os.Stdout and os.Stderr.
+func assertIsolated(t *testing.T, l *warnLogger, isolate func() (io.Writer, io.Writer, func()), write func(), expected []string) {
+	t.Helper()
+	origStdout, origStderr := os.Stdout, os.Stderr
+	defer func() { os.Stdout, os.Stderr = origStdout, origStderr }()
+	stdoutR, stdoutW, _ := os.Pipe()
+	stderrR, stderrW, _ := os.Pipe()
+	os.Stdout, os.Stderr = stdoutW, stderrW
+
+	stdout, stderr, restore := isolate()
+	write()
+	fmt.Fprint(stdout, "response")
+	fmt.Fprint(stderr, "error")
+	restore()
+	if os.Stdout != stdoutW || os.Stderr != stderrW {
+		t.Error("expected os.Stdout and os.Stderr to be restored")
+	}
+	stdoutW.Close()
+	stderrW.Close()
+
+	if out, _ := io.ReadAll(stdoutR); string(out) != "response" {
+		t.Errorf("expected stdout 'response' but got '%s'", out)
+	}
+	if out, _ := io.ReadAll(stderrR); string(out) != "error" {
+		t.Errorf("expected stderr 'error' but got '%s'", out)
+	}
+	if !reflect.DeepEqual(l.warnings, expected) {
+		t.Errorf("expected warnings %q but got %q", expected, l.warnings)
+	}
+}
+
+// warnLogger records the messages logged at warn level.
+type warnLogger struct {
+	discardLogger
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (l *warnLogger) Warnf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
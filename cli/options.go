@@ -0,0 +1,139 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// EnvTimeout is the name of the environment variable used to set the maximum duration of a plugin command,
+// e.g. "30s". It is ignored if WithTimeout option is used.
+const EnvTimeout = "NOTATION_PLUGIN_TIMEOUT"
+
+// EnvTranscript is the name of the environment variable used to set the path of the transcript file, see
+// WithTranscript. It is ignored if WithTranscript option is used.
+const EnvTranscript = "NOTATION_PLUGIN_TRANSCRIPT"
+
+// Option configures a CLI.
+type Option func(*CLI)
+
+// WithTimeout sets the maximum duration of a plugin command. Once the duration elapses, the context passed to the
+// plugin is cancelled and the CLI responds with plugin.ErrorCodeTimeout. Plugins must honour the context: a call
+// ignoring it keeps running in the background, which ties up the backend and, in a long-running CLI, eventually
+// throttles new commands.
+func WithTimeout(d time.Duration) Option {
+	return func(c *CLI) {
+		c.timeout = d
+	}
+}
+
+// WithStrictDecoding enables strict decoding of plugin requests. In strict mode, requests containing unknown fields,
+// data after the JSON value or more than maxRequestSize bytes are rejected with plugin.ErrorCodeValidation.
+// Unknown fields are reported with their path, e.g. "signature.criticalAttributes.foo".
+// A non-positive maxRequestSize disables the size limit.
+func WithStrictDecoding(maxRequestSize int64) Option {
+	return func(c *CLI) {
+		c.strict = true
+		c.maxRequestSize = maxRequestSize
+	}
+}
+
+// WithTranscript enables the transcript mode, in which the command, arguments, raw stdin, response, error, exit code
+// and duration of each invocation are appended to the file at given path as a JSON line.
+// The values of the fields set by WithTranscriptRedaction are redacted, which by default are payload, signature
+// and pluginConfig, and any stdin, stdout or stderr that isn't valid JSON is redacted entirely.
+func WithTranscript(path string) Option {
+	return func(c *CLI) {
+		c.transcript = path
+	}
+}
+
+// WithTranscriptRedaction sets the names of the JSON fields whose values are redacted in the transcript, at any
+// depth of the request, response and error. Calling it without any field disables redaction.
+func WithTranscriptRedaction(fields ...string) Option {
+	return func(c *CLI) {
+		c.redactions = fields
+	}
+}
+
+// WithRecorder enables the recording of plugin command invocations as fixtures in given directory, which must
+// exist. Each invocation is saved in its own file containing the arguments, the request, the response or error and
+// the exit code, without any redaction. The recorded fixtures can be replayed using the replay package.
+func WithRecorder(dir string) Option {
+	return func(c *CLI) {
+		c.fixtureDir = dir
+	}
+}
+
+// WithSocket sets the path of the Unix domain socket of the plugin daemon, which enables the forwarding of plugin
+// commands to the daemon listening on the socket. Commands are executed locally if no daemon is available, the
+// daemon doesn't run as the current user or it serves another plugin or version. Forwarded responses are validated
+// as if the plugin had returned them. It is also the default socket of the "serve" command, see ListenAndServe.
+// Forwarding is only supported on Linux, see ListenAndServe.
+func WithSocket(path string) Option {
+	return func(c *CLI) {
+		c.socket = path
+	}
+}
+
+// WithSignatureBatch enables the generate-signature-batch vendor command, which reads a JSON array or a stream of
+// newline-delimited generate-signature requests and responds with a JSON array containing the response or error of
+// each request, in the same order. Plugins implementing plugin.BatchSigner receive the whole batch at once,
+// otherwise up to concurrency requests are signed concurrently. A non-positive concurrency signs the requests
+// sequentially. Once the timeout set by WithTimeout elapses, the requests not completed yet fail with a timeout
+// error while the completed ones keep their result. The plugin must declare plugin.CapabilitySignatureGenerator.
+func WithSignatureBatch(concurrency int) Option {
+	return func(c *CLI) {
+		c.batch = true
+		c.concurrency = concurrency
+		if c.concurrency < 1 {
+			c.concurrency = 1
+		}
+	}
+}
+
+// WithExitCodes sets the process exit codes of plugin errors, overriding the default exit codes of given error
+// codes. By default, the CLI exits with 1 for ERROR, 2 for VALIDATION_ERROR, 3 for ACCESS_DENIED, 4 for TIMEOUT,
+// 5 for THROTTLED and 6 for UNSUPPORTED_CONTRACT_VERSION. Other error codes exit with the exit code of ERROR, and
+// errors not returned by a plugin command, e.g. invalid arguments, exit with 1.
+// Exit codes must be between 1 and 255, as notation expects a nonzero exit code in case of failure.
+func WithExitCodes(exitCodes map[plugin.ErrorCode]int) Option {
+	return func(c *CLI) {
+		if c.exitCodes == nil {
+			c.exitCodes = make(map[plugin.ErrorCode]int, len(exitCodes))
+		}
+		for code, exitCode := range exitCodes {
+			c.exitCodes[code] = exitCode
+		}
+	}
+}
+
+// applyEnv configures the CLI using environment variables for the settings that were not set through options.
+func (c *CLI) applyEnv() error {
+	if v := os.Getenv(EnvTimeout); v != "" && c.timeout == 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid %s value %q, expected a positive duration such as \"30s\"", EnvTimeout, v)
+		}
+		c.timeout = d
+	}
+	if c.transcript == "" {
+		c.transcript = os.Getenv(EnvTranscript)
+	}
+	return nil
+}
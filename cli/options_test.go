@@ -0,0 +1,54 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Setenv(EnvTimeout, "1m")
+	c, err := New(mock.NewPlugin(false), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	if c.timeout != time.Second {
+		t.Errorf("expected timeout to be %s but found %s", time.Second, c.timeout)
+	}
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	t.Setenv(EnvTimeout, "1m")
+	c, err := New(mock.NewPlugin(false))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+	if c.timeout != time.Minute {
+		t.Errorf("expected timeout to be %s but found %s", time.Minute, c.timeout)
+	}
+}
+
+func TestTimeoutFromEnvError(t *testing.T) {
+	for _, v := range []string{"invalid", "-1s", "0"} {
+		t.Run(v, func(t *testing.T) {
+			t.Setenv(EnvTimeout, v)
+			if _, err := New(mock.NewPlugin(false)); err == nil {
+				t.Errorf("New() expected error for %s=%q but not found", EnvTimeout, v)
+			}
+		})
+	}
+}
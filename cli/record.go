@@ -0,0 +1,126 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/fixture"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// invocation is the captured input and output of a single run of the CLI.
+type invocation struct {
+	start    time.Time
+	args     []string
+	stdin    []byte
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	duration time.Duration
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use, as the plugin may still be reading stdin after a timeout.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// runRecorded runs the given command, capturing its input and output, and records the invocation in the transcript
+// and as a fixture when enabled.
+// Failing to record the invocation is logged and doesn't affect the result of the command.
+func (c *CLI) runRecorded(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	start := time.Now()
+	var in lockedBuffer
+	var out, errOut bytes.Buffer
+	exitCode := c.run(ctx, args, io.TeeReader(stdin, &in), io.MultiWriter(stdout, &out), io.MultiWriter(stderr, &errOut))
+
+	inv := &invocation{
+		start:    start,
+		args:     args,
+		stdin:    in.Bytes(),
+		stdout:   out.Bytes(),
+		stderr:   errOut.Bytes(),
+		exitCode: exitCode,
+		duration: time.Since(start),
+	}
+	if c.transcript != "" {
+		if err := c.writeTranscript(inv); err != nil {
+			c.logger.Errorf("failed to write transcript to %s: %v", c.transcript, err)
+		}
+	}
+	if c.fixtureDir != "" {
+		if err := c.writeFixture(inv); err != nil {
+			c.logger.Errorf("failed to record fixture in %s: %v", c.fixtureDir, err)
+		}
+	}
+	return exitCode
+}
+
+// writeFixture saves given invocation as a fixture. Only the invocations of plugin commands are recorded.
+func (c *CLI) writeFixture(inv *invocation) error {
+	if len(inv.args) != 2 || inv.args[1] == string(plugin.Version) || isHelpArgs(inv.args) {
+		return nil
+	}
+
+	f := &fixture.Fixture{
+		Name:     fmt.Sprintf("%s-%d", inv.args[1], inv.start.UnixNano()),
+		Args:     []string{filepath.Base(inv.args[0]), inv.args[1]},
+		Command:  plugin.Command(inv.args[1]),
+		ExitCode: inv.exitCode,
+	}
+	var err error
+	if f.Request, err = rawJSON(inv.stdin); err != nil {
+		return fmt.Errorf("request is not a valid JSON: %w", err)
+	}
+	if f.Response, err = rawJSON(inv.stdout); err != nil {
+		return fmt.Errorf("response is not a valid JSON: %w", err)
+	}
+	if f.Error, err = rawJSON(inv.stderr); err != nil {
+		return fmt.Errorf("error is not a valid JSON: %w", err)
+	}
+	return f.Save(c.fixtureDir)
+}
+
+// rawJSON returns given data as a raw JSON value, or nil if data is empty.
+func rawJSON(data []byte) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("invalid JSON value")
+	}
+	return data, nil
+}
@@ -0,0 +1,62 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/fixture"
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestWithRecorder(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(mock.NewPlugin(false), WithRecorder(dir))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+
+	runs := []struct {
+		command plugin.Command
+		in      string
+	}{
+		{command: plugin.CommandGetMetadata, in: "{}"},
+		{command: plugin.CommandGetMetadata, in: "invalid"},
+		{command: plugin.Version},
+		{command: "help"},
+	}
+	for _, run := range runs {
+		var stdout, stderr bytes.Buffer
+		c.Run(context.Background(), []string{pluginExecutable, string(run.command)}, strings.NewReader(run.in), &stdout, &stderr)
+	}
+
+	fixtures, err := fixture.LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() failed with error: %v", err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("expected 1 fixture but found %d", len(fixtures))
+	}
+	f := fixtures[0]
+	if f.Command != plugin.CommandGetMetadata || string(f.Request) != "{}" || f.Error != nil || f.ExitCode != 0 {
+		t.Errorf("unexpected fixture %+v", f)
+	}
+	if f.Args[0] != "notation-com.example.plugin" {
+		t.Errorf("expected fixture args to contain executable base name but found %v", f.Args)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// serveCommand is the hidden command running the plugin as a daemon.
+const serveCommand = "serve"
+
+const jsonrpcVersion = "2.0"
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcPluginError    = -32000
+)
+
+// rpcRequest is a JSON-RPC 2.0 request, whose method is a plugin command and params is the plugin request.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response, whose result is the plugin response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error. Errors returned by the plugin are set as data.
+type rpcError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *plugin.Error `json:"data,omitempty"`
+}
+
+// ListenAndServe listens on the Unix domain socket at given path and serves the plugin until ctx is done.
+// A stale socket file left by a previous daemon is removed, and the socket is created only accessible by the
+// current user. See Serve for the protocol. The plugin daemon is only supported on Linux.
+func (c *CLI) ListenAndServe(ctx context.Context, socket string) error {
+	if fi, err := os.Lstat(socket); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			conn.Close()
+			return fmt.Errorf("socket %s is already in use", socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return fmt.Errorf("failed to remove stale socket %s: %w", socket, err)
+		}
+	}
+
+	l, err := listenUnix(socket)
+	if err != nil {
+		return err
+	}
+	c.logger.Debugf("serving plugin on %s", socket)
+	return c.Serve(ctx, l)
+}
+
+// Serve accepts connections on given listener and serves the plugin until ctx is done, then closes the listener and
+// waits for the active connections to be closed.
+// Each connection carries a stream of JSON-RPC 2.0 requests, whose method is a plugin command and params is the
+// corresponding plugin request, e.g. {"jsonrpc":"2.0","id":1,"method":"describe-key","params":{...}}.
+// The result of a response is the plugin response, and errors returned by the plugin are set as the data of a
+// JSON-RPC error with code -32000. Requests without id are executed but not answered.
+// Connections from processes running as another user are closed without being served, based on the peer
+// credentials of the Unix domain socket.
+// Serve fails without accepting connections if the plugin metadata can't be loaded. Plugin calls that don't return
+// once timed out are abandoned, see WithTimeout.
+func (c *CLI) Serve(ctx context.Context, l net.Listener) error {
+	if err := c.loadMetadata(ctx); err != nil {
+		l.Close()
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn serves the requests of given connection sequentially until the connection or ctx is closed.
+func (c *CLI) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	if err := checkPeer(conn); err != nil {
+		c.logger.Warnf("rejecting plugin daemon connection: %v", err)
+		return
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req rpcRequest
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || ctx.Err() != nil {
+				return
+			}
+			c.logger.Errorf("JSON-RPC request unmarshalling error: %v", err)
+			_ = enc.Encode(&rpcResponse{
+				JSONRPC: jsonrpcVersion,
+				Error:   &rpcError{Code: rpcParseError, Message: "parse error"},
+			})
+			return
+		}
+
+		resp := c.serveRequest(ctx, &req)
+		if req.ID == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			c.logger.Errorf("JSON-RPC response marshalling error: %v", err)
+			return
+		}
+	}
+}
+
+// serveRequest executes the plugin command of given JSON-RPC request.
+func (c *CLI) serveRequest(ctx context.Context, req *rpcRequest) *rpcResponse {
+	resp := &rpcResponse{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.JSONRPC != jsonrpcVersion || req.Method == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+	if !c.isPluginCommand(req.Method) {
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+		return resp
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	op, pluginErr := c.execRequest(ctx, plugin.Command(req.Method), bytes.NewReader(req.Params))
+	if pluginErr != nil {
+		resp.Error = &rpcError{Code: rpcPluginError, Message: pluginErr.Message, Data: pluginErr}
+		return resp
+	}
+	resp.Result = json.RawMessage(op)
+	return resp
+}
+
+// checkPeer checks that the process at the other end of given Unix domain socket connection runs as the current
+// user, so that requests are neither served to nor forwarded to another user.
+func checkPeer(conn net.Conn) error {
+	uid, err := peerUID(conn)
+	if err != nil {
+		return fmt.Errorf("failed to get peer credentials: %w", err)
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("peer runs as user %d instead of %d", uid, os.Getuid())
+	}
+	return nil
+}
+
+// serve runs the "serve" command with given flags.
+func (c *CLI) serve(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet(serveCommand, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	socket := fs.String("socket", c.socket, "")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("Invalid serve flags: %v", err)
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("Invalid serve arguments: %v", fs.Args())
+	}
+	if *socket == "" {
+		return errors.New("Invalid serve flags: --socket is required")
+	}
+	return c.ListenAndServe(ctx, *socket)
+}
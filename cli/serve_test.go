@@ -0,0 +1,353 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestServe(t *testing.T) {
+	socket := startDaemon(t, cli)
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to connect to daemon: %v", err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	tests := []struct {
+		name     string
+		req      string
+		expected string
+	}{
+		{
+			name:     "success",
+			req:      "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"get-plugin-metadata\",\"params\":{}}",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"name\":\"com.example.plugin\",\"description\":\"This is an description of example plugin. 🍺\",\"version\":\"1.0.0\",\"url\":\"https://example.com/notation/plugin\",\"supportedContractVersions\":[\"1.0\"],\"capabilities\":[\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\",\"SIGNATURE_GENERATOR.ENVELOPE\"]}}",
+		},
+		{
+			name:     "notification",
+			req:      "{\"jsonrpc\":\"2.0\",\"method\":\"get-plugin-metadata\",\"params\":{}}",
+			expected: "",
+		},
+		{
+			name:     "pluginError",
+			req:      "{\"jsonrpc\":\"2.0\",\"id\":\"2\",\"method\":\"generate-envelope\",\"params\":{\"contractVersion\":\"1.0\"}}",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":\"2\",\"error\":{\"code\":-32000,\"message\":\"Input is not a valid JSON: keyId cannot be empty\",\"data\":{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: keyId cannot be empty\"}}}",
+		},
+		{
+			name:     "methodNotFound",
+			req:      "{\"jsonrpc\":\"2.0\",\"id\":3,\"method\":\"describe-key\",\"params\":{}}",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":3,\"error\":{\"code\":-32601,\"message\":\"method \\\"describe-key\\\" not found\"}}",
+		},
+		{
+			name:     "version",
+			req:      "{\"jsonrpc\":\"2.0\",\"id\":4,\"method\":\"version\"}",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":4,\"error\":{\"code\":-32601,\"message\":\"method \\\"version\\\" not found\"}}",
+		},
+		{
+			name:     "invalidRequest",
+			req:      "{\"jsonrpc\":\"1.0\",\"id\":5,\"method\":\"get-plugin-metadata\"}",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":5,\"error\":{\"code\":-32600,\"message\":\"invalid request\"}}",
+		},
+		{
+			name:     "parseError",
+			req:      "{invalid",
+			expected: "{\"jsonrpc\":\"2.0\",\"id\":null,\"error\":{\"code\":-32700,\"message\":\"parse error\"}}",
+		},
+	}
+	for _, test := range tests {
+		if _, err := conn.Write([]byte(test.req + "\n")); err != nil {
+			t.Fatalf("%s: failed to write request: %v", test.name, err)
+		}
+		if test.expected == "" {
+			continue
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("%s: failed to read response: %v", test.name, err)
+		}
+		if strings.TrimSpace(line) != test.expected {
+			t.Errorf("%s: expected response '%s' but got '%s'", test.name, test.expected, line)
+		}
+	}
+}
+
+func TestListenAndServeStop(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- cli.ListenAndServe(ctx, socket)
+	}()
+	waitForSocket(t, socket)
+
+	fi, err := os.Stat(socket)
+	if err != nil {
+		t.Fatalf("failed to stat socket: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("expected socket to be only accessible by its owner but found permissions %o", perm)
+	}
+	if err := cli.ListenAndServe(context.Background(), socket); err == nil {
+		t.Errorf("ListenAndServe() expected error for a socket in use")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ListenAndServe() returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe() didn't return after the context was cancelled")
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("expected socket to be removed but found error: %v", err)
+	}
+}
+
+// replaceKeySpec is a daemon interceptor telling forwarded describe-key responses apart from local ones.
+func replaceKeySpec(ctx context.Context, command plugin.Command, req plugin.Request, next Handler) (any, error) {
+	resp, err := next(ctx, req)
+	if r, ok := resp.(*plugin.DescribeKeyResponse); ok {
+		r.KeySpec = plugin.KeySpecRSA3072
+	}
+	return resp, err
+}
+
+func TestShim(t *testing.T) {
+	daemon, _ := New(mock.NewSigGeneratorPlugin(false), WithInterceptors(replaceKeySpec))
+	socket := startDaemon(t, daemon)
+	shim, _ := New(mock.NewSigGeneratorPlugin(false), WithSocket(socket))
+
+	tests := map[string]struct {
+		in     string
+		stdout string
+		stderr string
+	}{
+		"forwarded": {
+			in:     "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}",
+			stdout: "{\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-3072\"}",
+		},
+		"forwardedError": {
+			in:     "{\"keyId\":\"someKeyId\"}",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: contractVersion cannot be empty\"}",
+		},
+		"invalidJSON": {
+			in:     "invalid",
+			stderr: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			shim.Run(context.Background(), []string{pluginExecutable, string(plugin.CommandDescribeKey)}, strings.NewReader(test.in), &stdout, &stderr)
+			if stdout.String() != test.stdout || stderr.String() != test.stderr {
+				t.Errorf("Run() expected stdout '%s' and stderr '%s' but got '%s' and '%s'", test.stdout, test.stderr, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestShimFallback(t *testing.T) {
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithSocket(filepath.Join(t.TempDir(), "plugin.sock")))
+	expectDescribeKey(t, c, "RSA-2048")
+}
+
+func TestShimInvalidResponse(t *testing.T) {
+	md, _ := mock.NewSigGeneratorPlugin(false).GetMetadata(context.Background(), &plugin.GetMetadataRequest{})
+	mdJSON, _ := json.Marshal(md)
+	socket := startFakeDaemon(t, map[string]string{
+		string(plugin.CommandGetMetadata): string(mdJSON),
+		string(plugin.CommandDescribeKey): "{\"keyId\":\"otherKeyId\",\"keySpec\":\"RSA-2048\"}",
+	})
+	c, _ := New(mock.NewSigGeneratorPlugin(false), WithSocket(socket))
+
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	c.Run(context.Background(), args, strings.NewReader("{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"), &stdout, &stderr)
+	expected := "{\"errorCode\":\"ERROR\",\"errorMessage\":\"Failed to generate response. Error: keyId \\\"otherKeyId\\\" doesn't match the requested keyId \\\"someKeyId\\\"\"}"
+	if stdout.Len() != 0 || stderr.String() != expected {
+		t.Errorf("Run() expected stderr '%s' but got stdout '%s' and stderr '%s'", expected, stdout.String(), stderr.String())
+	}
+}
+
+func TestShimPluginMismatch(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		version string
+	}{
+		"name":    {name: "com.example.other", version: "1.0.0"},
+		"version": {name: "com.example.plugin", version: "2.0.0"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pl := &renamedPlugin{Plugin: mock.NewSigGeneratorPlugin(false), name: test.name, version: test.version}
+			daemon, _ := New(pl, WithInterceptors(replaceKeySpec))
+			socket := startDaemon(t, daemon)
+
+			c, _ := New(mock.NewSigGeneratorPlugin(false), WithSocket(socket))
+			expectDescribeKey(t, c, "RSA-2048")
+		})
+	}
+}
+
+func TestRunServeError(t *testing.T) {
+	tests := map[string]struct {
+		args   []string
+		stderr string
+	}{
+		"missingSocket": {args: nil, stderr: "Invalid serve flags: --socket is required"},
+		"unknownFlag":   {args: []string{"--port", "80"}, stderr: "Invalid serve flags: flag provided but not defined: -port"},
+		"extraArgs":     {args: []string{"--socket", "plugin.sock", "extra"}, stderr: "Invalid serve arguments: [extra]"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{pluginExecutable, serveCommand}, test.args...)
+			if code := cli.Run(context.Background(), args, strings.NewReader(""), &stdout, &stderr); code != 1 {
+				t.Errorf("Run() expected exit code 1 but got %d", code)
+			}
+			if stderr.String() != test.stderr {
+				t.Errorf("Run() expected stderr '%s' but got '%s'", test.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestServeMetadataError(t *testing.T) {
+	c, _ := New(mock.NewSigGeneratorPlugin(true), WithDoctor())
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "plugin.sock"))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	if err := c.Serve(context.Background(), l); err == nil {
+		t.Error("Serve() expected error for plugin failing to return its metadata")
+	}
+}
+
+// renamedPlugin overrides the name and version of the metadata of given plugin.
+type renamedPlugin struct {
+	plugin.Plugin
+	name    string
+	version string
+}
+
+func (p *renamedPlugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	md, err := p.Plugin.GetMetadata(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	renamed := *md
+	renamed.Name, renamed.Version = p.name, p.version
+	return &renamed, nil
+}
+
+// expectDescribeKey runs the describe-key command of given CLI and checks the key spec of the response.
+func expectDescribeKey(t *testing.T, c *CLI, keySpec string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	args := []string{pluginExecutable, string(plugin.CommandDescribeKey)}
+	if code := c.Run(context.Background(), args, strings.NewReader("{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	expected := "{\"keyId\":\"someKeyId\",\"keySpec\":\"" + keySpec + "\"}"
+	if stdout.String() != expected {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expected, stdout.String())
+	}
+}
+
+// startDaemon runs the serve command of given CLI until the end of the test and returns the socket path.
+func startDaemon(t *testing.T, c *CLI) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	startDaemonOn(t, c, socket)
+	return socket
+}
+
+// startDaemonOn runs the serve command of given CLI on given socket until the end of the test.
+func startDaemonOn(t *testing.T, c *CLI, socket string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var stdout, stderr bytes.Buffer
+		c.Run(ctx, []string{pluginExecutable, serveCommand, "--socket", socket}, strings.NewReader(""), &stdout, &stderr)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitForSocket(t, socket)
+}
+
+// startFakeDaemon serves given JSON results by method on a Unix domain socket until the end of the test and returns
+// the socket path, standing for a daemon whose responses aren't validated.
+func startFakeDaemon(t *testing.T, results map[string]string) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+				for {
+					var req rpcRequest
+					if err := dec.Decode(&req); err != nil {
+						return
+					}
+					_ = enc.Encode(&rpcResponse{JSONRPC: jsonrpcVersion, ID: req.ID, Result: json.RawMessage(results[req.Method])})
+				}
+			}()
+		}
+	}()
+	return socket
+}
+
+func waitForSocket(t *testing.T, socket string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("daemon didn't listen on %s", socket)
+}
@@ -0,0 +1,159 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// forward sends given plugin request to the daemon listening on given socket and returns the marshalled response.
+// It returns false if the request must be executed locally, i.e. the daemon isn't available, doesn't run as the
+// current user or serves another plugin or version, or the request isn't a valid JSON, which can't be framed as
+// JSON-RPC params. The daemon response is validated against the request, as execute does for local responses.
+func (c *CLI) forward(ctx context.Context, socket string, command plugin.Command, data []byte) (string, *plugin.Error, bool) {
+	if !json.Valid(data) {
+		return "", nil, false
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socket)
+	if err != nil {
+		c.logger.Debugf("plugin daemon isn't available on %s, executing locally: %v", socket, err)
+		return "", nil, false
+	}
+	defer conn.Close()
+	// the daemon must run as the current user, otherwise another user could answer the requests of this one
+	if err := checkPeer(conn); err != nil {
+		c.logger.Warnf("plugin daemon on %s is rejected, executing locally: %v", socket, err)
+		return "", nil, false
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+
+	// the daemon must serve the same plugin and version as this executable, otherwise the request would be
+	// answered by another plugin of the same user sharing the socket
+	var md plugin.GetMetadataResponse
+	resp, err := call(enc, dec, plugin.CommandGetMetadata, []byte("{}"))
+	if err == nil && resp.Error != nil {
+		err = fmt.Errorf("plugin daemon error: %s", resp.Error.Message)
+	}
+	if err == nil {
+		err = json.Unmarshal(resp.Result, &md)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", c.contextError(ctx), true
+		}
+		c.logger.Warnf("plugin daemon on %s metadata handshake failed, executing locally: %v", socket, err)
+		return "", nil, false
+	}
+	if md.Name != c.md.Name || md.Version != c.md.Version {
+		c.logger.Warnf("plugin daemon on %s serves %s %s instead of %s %s, executing locally", socket, md.Name, md.Version, c.md.Name, c.md.Version)
+		return "", nil, false
+	}
+
+	c.logger.Debugf("forwarding %s command to plugin daemon on %s", command, socket)
+	resp, err = call(enc, dec, command, data)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", c.contextError(ctx), true
+		}
+		c.logger.Errorf("plugin daemon communication error: %v", err)
+		return "", plugin.NewGenericErrorf("failed to communicate with plugin daemon: %v", err), true
+	}
+
+	if resp.Error != nil {
+		if resp.Error.Data != nil {
+			return "", resp.Error.Data, true
+		}
+		return "", plugin.NewGenericErrorf("plugin daemon error: %s", resp.Error.Message), true
+	}
+	if err := c.validateForwarded(command, data, resp.Result); err != nil {
+		c.logger.Errorf("plugin daemon %s response validation error: %v", command, err)
+		var plError *plugin.Error
+		if errors.As(err, &plError) {
+			return "", plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, plError.Message), true
+		}
+		return "", plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, err.Error()), true
+	}
+	return string(resp.Result), nil, true
+}
+
+// validateForwarded validates the result returned by the daemon for given command and request, see
+// validateResponse. The results of vendor commands aren't validated, as with local execution.
+func (c *CLI) validateForwarded(command plugin.Command, data, result []byte) error {
+	var req plugin.Request
+	var resp any
+	switch command {
+	case plugin.CommandGetMetadata:
+		req, resp = &plugin.GetMetadataRequest{}, &plugin.GetMetadataResponse{}
+	case plugin.CommandDescribeKey:
+		req, resp = &plugin.DescribeKeyRequest{}, &plugin.DescribeKeyResponse{}
+	case plugin.CommandGenerateSignature:
+		req, resp = &plugin.GenerateSignatureRequest{}, &plugin.GenerateSignatureResponse{}
+	case plugin.CommandGenerateEnvelope:
+		req, resp = &plugin.GenerateEnvelopeRequest{}, &plugin.GenerateEnvelopeResponse{}
+	case plugin.CommandVerifySignature:
+		req, resp = &plugin.VerifySignatureRequest{}, &plugin.VerifySignatureResponse{}
+	case plugin.CommandGenerateSignatureBatch:
+		req, resp = &plugin.GenerateSignatureBatchRequest{}, &plugin.GenerateSignatureBatchResponse{}
+	default:
+		return nil
+	}
+
+	if err := c.unmarshalRequest(bytes.NewReader(data), req); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result, resp); err != nil {
+		return err
+	}
+	if batch, ok := resp.(*plugin.GenerateSignatureBatchResponse); ok {
+		return validateResponse(req, *batch)
+	}
+	return validateResponse(req, resp)
+}
+
+// call sends a JSON-RPC request for given plugin command and params, and reads its response.
+func call(enc *json.Encoder, dec *json.Decoder, command plugin.Command, params []byte) (*rpcResponse, error) {
+	req := &rpcRequest{
+		JSONRPC: jsonrpcVersion,
+		ID:      json.RawMessage("1"),
+		Method:  string(command),
+		Params:  params,
+	}
+	if err := enc.Encode(req); err != nil {
+		return nil, err
+	}
+	var resp rpcResponse
+	if err := dec.Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,55 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package cli
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// peerUID returns the user ID of the process at the other end of given Unix domain socket connection, as recorded by
+// the kernel when the connection was established, so that it can't be spoofed by the peer.
+func peerUID(conn net.Conn) (int, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errors.New("connection is not a Unix domain socket connection")
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(cred.Uid), nil
+}
+
+// listenUnix listens on the Unix domain socket at given path. The socket is created with a umask denying any access
+// to group and others, so that it is never accessible by other users, not even before its permissions could be
+// changed.
+func listenUnix(socket string) (net.Listener, error) {
+	mask := syscall.Umask(0o077)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", socket)
+}
@@ -0,0 +1,56 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package cli
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPeer(t *testing.T) {
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "plugin.sock"))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+	client, err := net.Dial("unix", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+	server, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %v", err)
+	}
+	defer server.Close()
+
+	for _, conn := range []net.Conn{client, server} {
+		if uid, err := peerUID(conn); err != nil || uid != os.Getuid() {
+			t.Errorf("peerUID() expected %d but got %d, error: %v", os.Getuid(), uid, err)
+		}
+		if err := checkPeer(conn); err != nil {
+			t.Errorf("checkPeer() failed with error: %v", err)
+		}
+	}
+
+	pipe, _ := net.Pipe()
+	defer pipe.Close()
+	if err := checkPeer(pipe); err == nil {
+		t.Error("checkPeer() expected error for a connection that isn't a Unix domain socket connection")
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package cli
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// peerUID returns the user ID of the process at the other end of given Unix domain socket connection. Peer
+// credentials are only supported on Linux, so the plugin daemon can't be reached on other platforms.
+func peerUID(_ net.Conn) (int, error) {
+	return 0, fmt.Errorf("peer credentials are not supported on %s", runtime.GOOS)
+}
+
+// listenUnix listens on the Unix domain socket at given path. The plugin daemon is only supported on Linux, see
+// peerUID.
+func listenUnix(_ string) (net.Listener, error) {
+	return nil, fmt.Errorf("plugin daemon is not supported on %s", runtime.GOOS)
+}
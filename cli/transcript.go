@@ -0,0 +1,141 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
+)
+
+// redactedValue replaces the value of redacted fields in the transcript.
+const redactedValue = "[redacted]"
+
+// defaultRedactions contains the names of the fields redacted in the transcript by default.
+var defaultRedactions = []string{"payload", "signature", "pluginConfig"}
+
+// transcriptEntry is a single invocation recorded in the transcript.
+type transcriptEntry struct {
+	Time     time.Time       `json:"time"`
+	Command  string          `json:"command,omitempty"`
+	Args     []string        `json:"args"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    json.RawMessage `json:"error,omitempty"`
+	ExitCode int             `json:"exitCode"`
+	Duration string          `json:"duration"`
+}
+
+// writeTranscript appends given invocation to the transcript file as a JSON line.
+func (c *CLI) writeTranscript(inv *invocation) error {
+	entry := transcriptEntry{
+		Time:     inv.start.UTC(),
+		Args:     inv.args,
+		Request:  c.redact(inv.stdin),
+		Response: c.redact(inv.stdout),
+		Error:    c.redact(inv.stderr),
+		ExitCode: inv.exitCode,
+		Duration: inv.duration.String(),
+	}
+	if len(inv.args) > 1 {
+		entry.Command = inv.args[1]
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.transcript, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// redact returns given data as a JSON value with the values of the redacted fields replaced. A stream of JSON
+// values, such as a newline-delimited generate-signature-batch request, is returned as an array of the redacted
+// values. Data that isn't valid JSON, such as malformed requests or the human-readable version output, is
+// entirely redacted unless redaction is disabled, in which case it's returned as a JSON string.
+func (c *CLI) redact(data []byte) json.RawMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	values, err := decodeValues(data)
+	if err != nil {
+		if len(c.redactions) == 0 {
+			s, _ := json.Marshal(string(data))
+			return s
+		}
+		s, _ := json.Marshal(redactedValue)
+		return s
+	}
+	var v any = values
+	if len(values) == 1 {
+		if len(c.redactions) == 0 {
+			return data
+		}
+		v = values[0]
+	}
+	redacted, err := json.Marshal(redactValue(v, c.redactions))
+	if err != nil {
+		s, _ := json.Marshal(redactedValue)
+		return s
+	}
+	return redacted
+}
+
+// decodeValues decodes the stream of JSON values of given data, failing if any of them isn't valid JSON.
+func decodeValues(data []byte) ([]any, error) {
+	var values []any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	for {
+		var v any
+		if err := dec.Decode(&v); err == io.EOF {
+			return values, nil
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+// redactValue replaces the values of given fields in v, at any depth.
+func redactValue(v any, fields []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if slices.Contains(fields, k) {
+				t[k] = redactedValue
+			} else {
+				t[k] = redactValue(val, fields)
+			}
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactValue(val, fields)
+		}
+	}
+	return v
+}
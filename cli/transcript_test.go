@@ -0,0 +1,192 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestRunWithTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	c, err := New(mock.NewSigGeneratorPlugin(false), WithTranscript(path))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+
+	signArgs := []string{pluginExecutable, string(plugin.CommandGenerateSignature)}
+	signIn := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\",\"pluginConfig\":{\"secret\":\"value\"}}"
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), signArgs, strings.NewReader(signIn), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+	expectedStdout := "{\"keyId\":\"someKeyId\",\"signature\":\"YWJjZA==\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]}"
+	if stdout.String() != expectedStdout {
+		t.Errorf("Run() expected stdout '%s' but got '%s'", expectedStdout, stdout.String())
+	}
+	if code := c.Run(context.Background(), []string{pluginExecutable, string(plugin.CommandDescribeKey)}, strings.NewReader("{}"), &stdout, &stderr); code != 2 {
+		t.Fatalf("Run() expected exit code 2 but got %d", code)
+	}
+
+	entries := readTranscript(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 transcript entries but found %d", len(entries))
+	}
+
+	sign := entries[0]
+	if sign.Command != string(plugin.CommandGenerateSignature) || sign.ExitCode != 0 || sign.Duration == "" || sign.Error != nil {
+		t.Errorf("unexpected transcript entry %+v", sign)
+	}
+	expectedReq := "{\"contractVersion\":\"1.0\",\"hashAlgorithm\":\"SHA-256\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"payload\":\"[redacted]\",\"pluginConfig\":\"[redacted]\"}"
+	if string(sign.Request) != expectedReq {
+		t.Errorf("expected transcript request '%s' but found '%s'", expectedReq, sign.Request)
+	}
+	expectedResp := "{\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"],\"keyId\":\"someKeyId\",\"signature\":\"[redacted]\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\"}"
+	if string(sign.Response) != expectedResp {
+		t.Errorf("expected transcript response '%s' but found '%s'", expectedResp, sign.Response)
+	}
+
+	describe := entries[1]
+	expectedErr := "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"Input is not a valid JSON: contractVersion cannot be empty\"}"
+	if describe.ExitCode != 2 || string(describe.Error) != expectedErr || describe.Response != nil {
+		t.Errorf("unexpected transcript entry %+v, expected error '%s'", describe, expectedErr)
+	}
+}
+
+func TestRunWithTranscriptBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	c, err := New(mock.NewSigGeneratorPlugin(false), WithSignatureBatch(1), WithTranscript(path))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+
+	req := "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-2048\",\"hashAlgorithm\":\"SHA-256\",\"payload\":\"em9w\"}"
+	inputs := map[string]string{
+		"ndjson":    req + "\n" + req + "\n",
+		"malformed": req + "\n{\"payload\":\"em9w\"",
+	}
+	for name, in := range inputs {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			c.Run(context.Background(), []string{pluginExecutable, string(plugin.CommandGenerateSignatureBatch)}, strings.NewReader(in), &stdout, &stderr)
+			entries := readTranscript(t, path)
+			entry := entries[len(entries)-1]
+			if strings.Contains(string(entry.Request), "em9w") {
+				t.Errorf("expected transcript request to be redacted but found '%s'", entry.Request)
+			}
+		})
+	}
+}
+
+func TestRunWithTranscriptFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	t.Setenv(EnvTranscript, path)
+	c, err := New(mock.NewPlugin(false), WithTranscriptRedaction())
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), []string{pluginExecutable, string(plugin.Version)}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+
+	entries := readTranscript(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 transcript entry but found %d", len(entries))
+	}
+	expectedResp, _ := json.Marshal(strings.TrimSpace(stdout.String()))
+	if string(entries[0].Response) != string(expectedResp) || entries[0].Request != nil {
+		t.Errorf("expected transcript response '%s' but found '%s'", expectedResp, entries[0].Response)
+	}
+}
+
+func TestRunWithTranscriptError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "transcript.jsonl")
+	c, err := New(mock.NewPlugin(false), WithTranscript(path))
+	if err != nil {
+		t.Fatalf("New() failed with error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), []string{pluginExecutable, string(plugin.Version)}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Errorf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	c := &CLI{redactions: []string{"secret"}}
+	tests := map[string]struct {
+		in       string
+		expected string
+	}{
+		"empty":    {in: " \n", expected: ""},
+		"text":     {in: "some text\n", expected: "\"[redacted]\""},
+		"nested":   {in: "{\"a\":[{\"secret\":{\"b\":1}}],\"secret\":\"s\",\"c\":1.50}", expected: "{\"a\":[{\"secret\":\"[redacted]\"}],\"c\":1.50,\"secret\":\"[redacted]\"}"},
+		"invalid":  {in: "{\"secret\":", expected: "\"[redacted]\""},
+		"trailing": {in: "{\"secret\":\"s\"}garbage", expected: "\"[redacted]\""},
+		"stream":   {in: "{\"secret\":\"s\",\"a\":1}\n{\"secret\":\"t\"}\n", expected: "[{\"a\":1,\"secret\":\"[redacted]\"},{\"secret\":\"[redacted]\"}]"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if redacted := c.redact([]byte(test.in)); string(redacted) != test.expected {
+				t.Errorf("redact() expected '%s' but got '%s'", test.expected, redacted)
+			}
+		})
+	}
+}
+
+func TestRedactDisabled(t *testing.T) {
+	c := &CLI{}
+	tests := map[string]struct {
+		in       string
+		expected string
+	}{
+		"text":   {in: "some text\n", expected: "\"some text\""},
+		"json":   {in: "{\"secret\":\"s\"}", expected: "{\"secret\":\"s\"}"},
+		"stream": {in: "{\"a\":1}\n{\"b\":2}", expected: "[{\"a\":1},{\"b\":2}]"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if redacted := c.redact([]byte(test.in)); string(redacted) != test.expected {
+				t.Errorf("redact() expected '%s' but got '%s'", test.expected, redacted)
+			}
+		})
+	}
+}
+
+func readTranscript(t *testing.T, path string) []transcriptEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read transcript: %v", err)
+	}
+	var entries []transcriptEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry transcriptEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid transcript line '%s': %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
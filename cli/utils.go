@@ -15,7 +15,9 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 
@@ -23,8 +25,8 @@ import (
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
-func getValidArgsString(md *plugin.GetMetadataResponse) string {
-	return fmt.Sprintf(`<%s>`, strings.Join(getValidArgs(md), "|"))
+func getValidArgsString(validArgs []string) string {
+	return fmt.Sprintf(`<%s>`, strings.Join(validArgs, "|"))
 }
 
 // getValidArgs returns list of valid arguments depending upon the plugin capabilities
@@ -49,30 +51,178 @@ func getValidArgs(md *plugin.GetMetadataResponse) []string {
 	return args
 }
 
-// deliverError print to standard error and then return nonzero exit code
-func deliverError(message string) {
-	_, _ = fmt.Fprint(os.Stderr, message)
-	os.Exit(1)
+// validArgs returns list of valid arguments of the CLI, i.e. the ones supported by the plugin capabilities along
+// with the enabled vendor commands.
+func (c *CLI) validArgs() []string {
+	args := append(getValidArgs(c.md), c.vendorCommands()...)
+	sort.Strings(args)
+	return args
+}
+
+// isPluginCommand returns true if given name is a plugin command supported by the CLI, i.e. a valid argument
+// other than version and doctor.
+func (c *CLI) isPluginCommand(name string) bool {
+	return name != string(plugin.Version) && name != doctorCommand && slices.Contains(c.validArgs(), name)
+}
+
+// validateExecutableName checks that the name of the plugin executable is plugin.BinaryPrefix followed by the
+// plugin name, as expected by notation.
+func validateExecutableName(md *plugin.GetMetadataResponse, executable string) error {
+	name := filepath.Base(executable)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+	if expected := plugin.BinaryPrefix + md.Name; name != expected {
+		return fmt.Errorf("Invalid plugin executable name %q, expected %q", name, expected)
+	}
+	return nil
+}
+
+// validateImplementation checks that the plugin implements the interfaces required by the capabilities declared in
+// its metadata.
+func validateImplementation(pl plugin.GenericPlugin, md *plugin.GetMetadataResponse) error {
+	for _, capability := range md.Capabilities {
+		var implemented bool
+		var iface string
+		switch capability {
+		case plugin.CapabilitySignatureGenerator:
+			_, implemented = pl.(plugin.SignatureGeneratorPlugin)
+			iface = "plugin.SignatureGeneratorPlugin"
+		case plugin.CapabilityEnvelopeGenerator:
+			_, implemented = pl.(plugin.EnvelopeGeneratorPlugin)
+			iface = "plugin.EnvelopeGeneratorPlugin"
+		case plugin.CapabilityTrustedIdentityVerifier, plugin.CapabilityRevocationCheckVerifier:
+			_, implemented = pl.(plugin.VerifyPlugin)
+			iface = "plugin.VerifyPlugin"
+		default:
+			continue
+		}
+		if !implemented {
+			return fmt.Errorf("plugin declares capability %q but doesn't implement %s", capability, iface)
+		}
+	}
+	return nil
 }
 
-// deferStdout is used to make sure that nothing get emitted to stdout and stderr until intentionally rescued.
-// This is required to make sure that the plugin or its dependency doesn't interfere with notation <-> plugin communication
-func deferStdout() func() {
-	// Ignoring error because we don't want plugin to fail if `os.DevNull` is misconfigured.
-	null, _ := os.Open(os.DevNull)
-	sout := os.Stdout
-	serr := os.Stderr
-	os.Stdout = null
-	os.Stderr = null
+// validateContractVersion checks that the contract version of given request is one of the contract versions
+// supported by the plugin.
+func validateContractVersion(md *plugin.GetMetadataResponse, req plugin.Request) error {
+	var version string
+	switch r := req.(type) {
+	case *plugin.DescribeKeyRequest:
+		version = r.ContractVersion
+	case *plugin.GenerateSignatureRequest:
+		version = r.ContractVersion
+	case *plugin.GenerateEnvelopeRequest:
+		version = r.ContractVersion
+	case *plugin.VerifySignatureRequest:
+		version = r.ContractVersion
+	default:
+		return nil
+	}
+
+	if !slices.Contains(md.SupportedContractVersions, version) {
+		return plugin.NewUnsupportedContractVersionError(version)
+	}
+	return nil
+}
+
+// validateRequestedCapabilities checks that the signature verification capabilities requested by the trust policy
+// of a verify-signature request are declared by the plugin.
+func validateRequestedCapabilities(md *plugin.GetMetadataResponse, req plugin.Request) error {
+	r, ok := req.(*plugin.VerifySignatureRequest)
+	if !ok {
+		return nil
+	}
+
+	for _, capability := range r.TrustPolicy.SignatureVerification {
+		if !md.HasCapability(capability) {
+			return plugin.NewUnsupportedError(fmt.Sprintf("signature verification capability %q", capability))
+		}
+	}
+	return nil
+}
+
+// validateResponse validates the response returned for given request. A nil response is reported as an empty
+// response.
+func validateResponse(req plugin.Request, resp any) error {
+	switch r := req.(type) {
+	case *plugin.GetMetadataRequest:
+		if res, ok := resp.(*plugin.GetMetadataResponse); ok || resp == nil {
+			return res.Validate()
+		}
+	case *plugin.DescribeKeyRequest:
+		if res, ok := resp.(*plugin.DescribeKeyResponse); ok || resp == nil {
+			return res.Validate(r)
+		}
+	case *plugin.GenerateSignatureRequest:
+		if res, ok := resp.(*plugin.GenerateSignatureResponse); ok || resp == nil {
+			return res.Validate(r)
+		}
+	case *plugin.GenerateEnvelopeRequest:
+		if res, ok := resp.(*plugin.GenerateEnvelopeResponse); ok || resp == nil {
+			return res.Validate(r)
+		}
+	case *plugin.VerifySignatureRequest:
+		if res, ok := resp.(*plugin.VerifySignatureResponse); ok || resp == nil {
+			return res.Validate(r)
+		}
+	case *plugin.GenerateSignatureBatchRequest:
+		if res, ok := resp.(plugin.GenerateSignatureBatchResponse); ok || resp == nil {
+			return res.Validate(*r)
+		}
+	default:
+		return nil
+	}
+	return fmt.Errorf("unexpected response type %T", resp)
+}
+
+// exitCodeGeneric is the exit code of errors that aren't plugin errors, e.g. invalid arguments.
+const exitCodeGeneric = 1
+
+// defaultExitCodes maps plugin error codes to the process exit codes used unless overridden by WithExitCodes.
+var defaultExitCodes = map[plugin.ErrorCode]int{
+	plugin.ErrorCodeGeneric:                    exitCodeGeneric,
+	plugin.ErrorCodeValidation:                 2,
+	plugin.ErrorCodeAccessDenied:               3,
+	plugin.ErrorCodeTimeout:                    4,
+	plugin.ErrorCodeThrottled:                  5,
+	plugin.ErrorCodeUnsupportedContractVersion: 6,
+}
+
+// deliverError prints to given standard error writer and then returns nonzero exit code
+func deliverError(stderr io.Writer, message string) int {
+	_, _ = fmt.Fprint(stderr, message)
+	return exitCodeGeneric
+}
+
+// deliverPluginError prints given plugin error to given standard error writer and then returns the exit code
+// mapped to its error code.
+func (c *CLI) deliverPluginError(stderr io.Writer, err *plugin.Error) int {
+	_, _ = fmt.Fprint(stderr, err.Error())
+	return c.exitCode(err.ErrCode)
+}
+
+// exitCode returns the exit code mapped to given plugin error code. Unknown error codes are mapped to the exit code
+// of plugin.ErrorCodeGeneric.
+func (c *CLI) exitCode(code plugin.ErrorCode) int {
+	if exitCode, ok := c.exitCodes[code]; ok {
+		return exitCode
+	}
+	if exitCode, ok := defaultExitCodes[code]; ok {
+		return exitCode
+	}
+	return c.exitCode(plugin.ErrorCodeGeneric)
+}
 
-	return func() {
-		err := null.Close()
-		if err != nil {
-			return
+// validateExitCodes checks that given exit codes are valid nonzero process exit codes.
+func validateExitCodes(exitCodes map[plugin.ErrorCode]int) error {
+	for code, exitCode := range exitCodes {
+		if exitCode < 1 || exitCode > 255 {
+			return fmt.Errorf("invalid exit code %d for error code %q, expected a value between 1 and 255", exitCode, code)
 		}
-		os.Stdout = sout
-		os.Stderr = serr
 	}
+	return nil
 }
 
 // discardLogger implements Logger but logs nothing. It is used when user
@@ -14,10 +14,12 @@
 package cli
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
@@ -32,7 +34,7 @@ func TestGetValidArgsString(t *testing.T) {
 			plugin.CapabilityTrustedIdentityVerifier,
 			plugin.CapabilityRevocationCheckVerifier},
 	}
-	s := getValidArgsString(&mdResp)
+	s := getValidArgsString(getValidArgs(&mdResp))
 	expected := "<generate-envelope|get-plugin-metadata|verify-signature|version>"
 	if !strings.EqualFold(s, expected) {
 		t.Errorf("Expected %s but found %s", expected, s)
@@ -77,3 +79,123 @@ func TestGetValidArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateContractVersion(t *testing.T) {
+	tests := map[string]struct {
+		supported []string
+		req       plugin.Request
+	}{
+		"declared":       {supported: []string{"1.0", "1.1"}, req: &plugin.DescribeKeyRequest{ContractVersion: "1.1", KeyID: "someKeyId"}},
+		"withoutVersion": {supported: []string{"1.0"}, req: &plugin.GetMetadataRequest{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			md := &plugin.GetMetadataResponse{SupportedContractVersions: test.supported}
+			if err := validateContractVersion(md, test.req); err != nil {
+				t.Errorf("validateContractVersion() failed with error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateContractVersionError(t *testing.T) {
+	tests := map[string]struct {
+		supported []string
+		req       plugin.Request
+	}{
+		"generateEnvelope": {supported: []string{"1.0"}, req: &plugin.GenerateEnvelopeRequest{ContractVersion: "2.0"}},
+		"verifySignature":  {supported: []string{"1.0", "1.1"}, req: &plugin.VerifySignatureRequest{ContractVersion: "2.0"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			md := &plugin.GetMetadataResponse{SupportedContractVersions: test.supported}
+			err := validateContractVersion(md, test.req)
+			expected := "{\"errorCode\":\"UNSUPPORTED_CONTRACT_VERSION\",\"errorMessage\":\"\\\"2.0\\\" is not a supported notary plugin contract version\"}"
+			if err == nil || err.Error() != expected {
+				t.Errorf("validateContractVersion() expected error '%s' but found '%v'", expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestedCapabilities(t *testing.T) {
+	md := &plugin.GetMetadataResponse{Capabilities: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}}
+	req := &plugin.VerifySignatureRequest{TrustPolicy: plugin.TrustPolicy{SignatureVerification: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}}}
+	if err := validateRequestedCapabilities(md, req); err != nil {
+		t.Errorf("validateRequestedCapabilities() failed with error: %v", err)
+	}
+
+	req.TrustPolicy.SignatureVerification = append(req.TrustPolicy.SignatureVerification, plugin.CapabilityRevocationCheckVerifier)
+	err := validateRequestedCapabilities(md, req)
+	assertErr(t, err, plugin.ErrorCodeValidation)
+}
+
+func TestValidateResponse(t *testing.T) {
+	req := &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "someKeyId"}
+	resp := &plugin.DescribeKeyResponse{KeyID: "someKeyId", KeySpec: plugin.KeySpecRSA2048}
+	if err := validateResponse(req, resp); err != nil {
+		t.Errorf("validateResponse() failed with error: %v", err)
+	}
+}
+
+func TestValidateResponseError(t *testing.T) {
+	tests := map[string]struct {
+		req  plugin.Request
+		resp any
+		err  string
+	}{
+		"nilResponse": {
+			req:  &plugin.GenerateSignatureRequest{KeyID: "someKeyId"},
+			resp: nil,
+			err:  "{\"errorCode\":\"ERROR\",\"errorMessage\":\"response cannot be empty\"}",
+		},
+		"typedNilResponse": {
+			req:  &plugin.GetMetadataRequest{},
+			resp: (*plugin.GetMetadataResponse)(nil),
+			err:  "{\"errorCode\":\"ERROR\",\"errorMessage\":\"response cannot be empty\"}",
+		},
+		"unexpectedResponse": {
+			req:  &plugin.GenerateEnvelopeRequest{},
+			resp: &plugin.DescribeKeyResponse{},
+			err:  "unexpected response type *plugin.DescribeKeyResponse",
+		},
+		"verificationResults": {
+			req:  &plugin.VerifySignatureRequest{TrustPolicy: plugin.TrustPolicy{SignatureVerification: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}}},
+			resp: &plugin.VerifySignatureResponse{},
+			err:  "{\"errorCode\":\"ERROR\",\"errorMessage\":\"verificationResults doesn't contain result for requested capability \\\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\\\"\"}",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateResponse(test.req, test.resp)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("validateResponse() expected error '%s' but found '%v'", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateImplementation(t *testing.T) {
+	md := &plugin.GetMetadataResponse{Capabilities: []plugin.Capability{plugin.CapabilityEnvelopeGenerator, plugin.CapabilityTrustedIdentityVerifier}}
+	if err := validateImplementation(mock.NewPlugin(false), md); err != nil {
+		t.Errorf("validateImplementation() failed with error: %v", err)
+	}
+}
+
+func TestValidateImplementationError(t *testing.T) {
+	tests := map[string]plugin.Capability{
+		"plugin.SignatureGeneratorPlugin": plugin.CapabilitySignatureGenerator,
+		"plugin.EnvelopeGeneratorPlugin":  plugin.CapabilityEnvelopeGenerator,
+		"plugin.VerifyPlugin":             plugin.CapabilityRevocationCheckVerifier,
+	}
+	for iface, capability := range tests {
+		t.Run(iface, func(t *testing.T) {
+			md := &plugin.GetMetadataResponse{Capabilities: []plugin.Capability{capability}}
+			err := validateImplementation(&metadataPlugin{md: md}, md)
+			expected := fmt.Sprintf("plugin declares capability %q but doesn't implement %s", capability, iface)
+			if err == nil || err.Error() != expected {
+				t.Errorf("validateImplementation() expected error '%s' but found '%v'", expected, err)
+			}
+		})
+	}
+}
@@ -26,14 +26,6 @@ func NewExamplePlugin() (*ExamplePlugin, error) {
 	return &ExamplePlugin{}, nil
 }
 
-func (p *ExamplePlugin) DescribeKey(_ context.Context, _ *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
-	return nil, plugin.NewUnsupportedError("DescribeKey operation is not implemented by example plugin")
-}
-
-func (p *ExamplePlugin) GenerateSignature(_ context.Context, _ *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
-	return nil, plugin.NewUnsupportedError("GenerateSignature operation is not implemented by example plugin")
-}
-
 func (p *ExamplePlugin) GenerateEnvelope(_ context.Context, _ *plugin.GenerateEnvelopeRequest) (*plugin.GenerateEnvelopeResponse, error) {
 	sig := "eyJwYXlsb2FkIjoiZXlKMFlYSm5aWFJCY25ScFptRmpkQ0k2ZXlKa2FXZGxjM1FpT2lKemFHRXlOVFk2Wm1VM1pUa3pNek16T1RVd05qQmpNbVkxWlRZelkyWXpObUV6" +
 		"T0daaVlURXdNVGMyWmpFNE0ySTBNVFl6WVRVM09UUmxNRGd4WVRRNE1HRmlZbUUxWmlJc0ltMWxaR2xoVkhsd1pTSTZJbUZ3Y0d4cFkyRjBhVzl1TDNadVpDNWtiMk5yWlh" +
@@ -74,18 +66,25 @@ func (p *ExamplePlugin) VerifySignature(_ context.Context, req *plugin.VerifySig
 		pAttrs[i] = upAttrs[i]
 	}
 
-	return &plugin.VerifySignatureResponse{
-		ProcessedAttributes: pAttrs,
-		VerificationResults: map[plugin.Capability]*plugin.VerificationResult{
-			plugin.CapabilityTrustedIdentityVerifier: {
+	results := make(map[plugin.Capability]*plugin.VerificationResult)
+	for _, capability := range req.TrustPolicy.SignatureVerification {
+		switch capability {
+		case plugin.CapabilityTrustedIdentityVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Valid trusted Identity",
-			},
-			plugin.CapabilityRevocationCheckVerifier: {
+			}
+		case plugin.CapabilityRevocationCheckVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Not revoked",
-			},
-		},
+			}
+		}
+	}
+
+	return &plugin.VerifySignatureResponse{
+		ProcessedAttributes: pAttrs,
+		VerificationResults: results,
 	}, nil
 }
 
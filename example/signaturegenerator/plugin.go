@@ -37,15 +37,11 @@ func (p *ExamplePlugin) GenerateSignature(_ context.Context, req *plugin.Generat
 	return &plugin.GenerateSignatureResponse{
 		KeyID:            req.KeyID,
 		Signature:        []byte("generatedMockSignature"),
-		SigningAlgorithm: plugin.SignatureAlgorithmRSASSA_PSS_SHA384,
+		SigningAlgorithm: req.KeySpec.SignatureAlgorithm(),
 		CertificateChain: [][]byte{[]byte("mockCert1"), []byte("mockCert2")},
 	}, nil
 }
 
-func (p *ExamplePlugin) GenerateEnvelope(_ context.Context, _ *plugin.GenerateEnvelopeRequest) (*plugin.GenerateEnvelopeResponse, error) {
-	return nil, plugin.NewUnsupportedError("GenerateSignature operation is not implemented by example plugin")
-}
-
 func (p *ExamplePlugin) VerifySignature(_ context.Context, req *plugin.VerifySignatureRequest) (*plugin.VerifySignatureResponse, error) {
 	upAttrs := req.Signature.UnprocessedAttributes
 	pAttrs := make([]interface{}, len(upAttrs))
@@ -53,18 +49,25 @@ func (p *ExamplePlugin) VerifySignature(_ context.Context, req *plugin.VerifySig
 		pAttrs[i] = upAttrs[i]
 	}
 
-	return &plugin.VerifySignatureResponse{
-		ProcessedAttributes: pAttrs,
-		VerificationResults: map[plugin.Capability]*plugin.VerificationResult{
-			plugin.CapabilityTrustedIdentityVerifier: {
+	results := make(map[plugin.Capability]*plugin.VerificationResult)
+	for _, capability := range req.TrustPolicy.SignatureVerification {
+		switch capability {
+		case plugin.CapabilityTrustedIdentityVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Valid trusted Identity",
-			},
-			plugin.CapabilityRevocationCheckVerifier: {
+			}
+		case plugin.CapabilityRevocationCheckVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Not revoked",
-			},
-		},
+			}
+		}
+	}
+
+	return &plugin.VerifySignatureResponse{
+		ProcessedAttributes: pAttrs,
+		VerificationResults: results,
 	}, nil
 }
 
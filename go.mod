@@ -1,3 +1,16 @@
 module github.com/notaryproject/notation-plugin-framework-go
 
 go 1.20
+
+require (
+	google.golang.org/grpc v1.60.1
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231002182017-d307bd883b97 // indirect
+)
@@ -0,0 +1,84 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-plugin-framework-go/grpcplugin/pluginpb"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"google.golang.org/grpc"
+)
+
+// Client is a plugin.Plugin calling a plugin served over gRPC, e.g. by Server.
+// Errors are returned as *plugin.Error.
+type Client struct {
+	c pluginpb.PluginClient
+}
+
+var _ plugin.Plugin = (*Client)(nil)
+
+// NewClient creates a new Client using given gRPC connection.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{c: pluginpb.NewPluginClient(cc)}
+}
+
+// GetMetadata returns the plugin metadata.
+func (c *Client) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	resp, err := c.c.GetMetadata(ctx, toProtoGetMetadataRequest(req))
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	return fromProtoGetMetadataResponse(resp), nil
+}
+
+// DescribeKey describes the given key.
+func (c *Client) DescribeKey(ctx context.Context, req *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
+	resp, err := c.c.DescribeKey(ctx, toProtoDescribeKeyRequest(req))
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	return fromProtoDescribeKeyResponse(resp), nil
+}
+
+// GenerateSignature generates the raw signature of the given payload.
+func (c *Client) GenerateSignature(ctx context.Context, req *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	resp, err := c.c.GenerateSignature(ctx, toProtoGenerateSignatureRequest(req))
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	return fromProtoGenerateSignatureResponse(resp), nil
+}
+
+// GenerateEnvelope generates the signature envelope of the given payload.
+func (c *Client) GenerateEnvelope(ctx context.Context, req *plugin.GenerateEnvelopeRequest) (*plugin.GenerateEnvelopeResponse, error) {
+	resp, err := c.c.GenerateEnvelope(ctx, toProtoGenerateEnvelopeRequest(req))
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	return fromProtoGenerateEnvelopeResponse(resp), nil
+}
+
+// VerifySignature verifies the given signature.
+func (c *Client) VerifySignature(ctx context.Context, req *plugin.VerifySignatureRequest) (*plugin.VerifySignatureResponse, error) {
+	pReq, err := toProtoVerifySignatureRequest(req)
+	if err != nil {
+		return nil, plugin.NewValidationError(err.Error())
+	}
+	resp, err := c.c.VerifySignature(ctx, pReq)
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	return fromProtoVerifySignatureResponse(resp), nil
+}
@@ -0,0 +1,290 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/grpcplugin/pluginpb"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoGetMetadataRequest(req *plugin.GetMetadataRequest) *pluginpb.GetMetadataRequest {
+	return &pluginpb.GetMetadataRequest{PluginConfig: req.PluginConfig}
+}
+
+func fromProtoGetMetadataRequest(req *pluginpb.GetMetadataRequest) *plugin.GetMetadataRequest {
+	return &plugin.GetMetadataRequest{PluginConfig: req.GetPluginConfig()}
+}
+
+func toProtoGetMetadataResponse(resp *plugin.GetMetadataResponse) (*pluginpb.GetMetadataResponse, error) {
+	capabilities := make([]string, len(resp.Capabilities))
+	for i, c := range resp.Capabilities {
+		capabilities[i] = string(c)
+	}
+	return &pluginpb.GetMetadataResponse{
+		Name:                      resp.Name,
+		Description:               resp.Description,
+		Version:                   resp.Version,
+		Url:                       resp.URL,
+		SupportedContractVersions: resp.SupportedContractVersions,
+		Capabilities:              capabilities,
+	}, nil
+}
+
+func fromProtoGetMetadataResponse(resp *pluginpb.GetMetadataResponse) *plugin.GetMetadataResponse {
+	capabilities := make([]plugin.Capability, len(resp.GetCapabilities()))
+	for i, c := range resp.GetCapabilities() {
+		capabilities[i] = plugin.Capability(c)
+	}
+	return &plugin.GetMetadataResponse{
+		Name:                      resp.GetName(),
+		Description:               resp.GetDescription(),
+		Version:                   resp.GetVersion(),
+		URL:                       resp.GetUrl(),
+		SupportedContractVersions: resp.GetSupportedContractVersions(),
+		Capabilities:              capabilities,
+	}
+}
+
+func toProtoDescribeKeyRequest(req *plugin.DescribeKeyRequest) *pluginpb.DescribeKeyRequest {
+	return &pluginpb.DescribeKeyRequest{
+		ContractVersion: req.ContractVersion,
+		KeyId:           req.KeyID,
+		PluginConfig:    req.PluginConfig,
+	}
+}
+
+func fromProtoDescribeKeyRequest(req *pluginpb.DescribeKeyRequest) *plugin.DescribeKeyRequest {
+	return &plugin.DescribeKeyRequest{
+		ContractVersion: req.GetContractVersion(),
+		KeyID:           req.GetKeyId(),
+		PluginConfig:    req.GetPluginConfig(),
+	}
+}
+
+func toProtoDescribeKeyResponse(resp *plugin.DescribeKeyResponse) (*pluginpb.DescribeKeyResponse, error) {
+	return &pluginpb.DescribeKeyResponse{
+		KeyId:   resp.KeyID,
+		KeySpec: string(resp.KeySpec),
+	}, nil
+}
+
+func fromProtoDescribeKeyResponse(resp *pluginpb.DescribeKeyResponse) *plugin.DescribeKeyResponse {
+	return &plugin.DescribeKeyResponse{
+		KeyID:   resp.GetKeyId(),
+		KeySpec: plugin.KeySpec(resp.GetKeySpec()),
+	}
+}
+
+func toProtoGenerateSignatureRequest(req *plugin.GenerateSignatureRequest) *pluginpb.GenerateSignatureRequest {
+	return &pluginpb.GenerateSignatureRequest{
+		ContractVersion: req.ContractVersion,
+		KeyId:           req.KeyID,
+		KeySpec:         string(req.KeySpec),
+		HashAlgorithm:   string(req.Hash),
+		Payload:         req.Payload,
+		PluginConfig:    req.PluginConfig,
+	}
+}
+
+func fromProtoGenerateSignatureRequest(req *pluginpb.GenerateSignatureRequest) *plugin.GenerateSignatureRequest {
+	return &plugin.GenerateSignatureRequest{
+		ContractVersion: req.GetContractVersion(),
+		KeyID:           req.GetKeyId(),
+		KeySpec:         plugin.KeySpec(req.GetKeySpec()),
+		Hash:            plugin.HashAlgorithm(req.GetHashAlgorithm()),
+		Payload:         req.GetPayload(),
+		PluginConfig:    req.GetPluginConfig(),
+	}
+}
+
+func toProtoGenerateSignatureResponse(resp *plugin.GenerateSignatureResponse) (*pluginpb.GenerateSignatureResponse, error) {
+	return &pluginpb.GenerateSignatureResponse{
+		KeyId:            resp.KeyID,
+		Signature:        resp.Signature,
+		SigningAlgorithm: string(resp.SigningAlgorithm),
+		CertificateChain: resp.CertificateChain,
+	}, nil
+}
+
+func fromProtoGenerateSignatureResponse(resp *pluginpb.GenerateSignatureResponse) *plugin.GenerateSignatureResponse {
+	return &plugin.GenerateSignatureResponse{
+		KeyID:            resp.GetKeyId(),
+		Signature:        resp.GetSignature(),
+		SigningAlgorithm: plugin.SignatureAlgorithm(resp.GetSigningAlgorithm()),
+		CertificateChain: resp.GetCertificateChain(),
+	}
+}
+
+func toProtoGenerateEnvelopeRequest(req *plugin.GenerateEnvelopeRequest) *pluginpb.GenerateEnvelopeRequest {
+	return &pluginpb.GenerateEnvelopeRequest{
+		ContractVersion:         req.ContractVersion,
+		KeyId:                   req.KeyID,
+		PayloadType:             req.PayloadType,
+		SignatureEnvelopeType:   req.SignatureEnvelopeType,
+		Payload:                 req.Payload,
+		ExpiryDurationInSeconds: req.ExpiryDurationInSeconds,
+		PluginConfig:            req.PluginConfig,
+	}
+}
+
+func fromProtoGenerateEnvelopeRequest(req *pluginpb.GenerateEnvelopeRequest) *plugin.GenerateEnvelopeRequest {
+	return &plugin.GenerateEnvelopeRequest{
+		ContractVersion:         req.GetContractVersion(),
+		KeyID:                   req.GetKeyId(),
+		PayloadType:             req.GetPayloadType(),
+		SignatureEnvelopeType:   req.GetSignatureEnvelopeType(),
+		Payload:                 req.GetPayload(),
+		ExpiryDurationInSeconds: req.GetExpiryDurationInSeconds(),
+		PluginConfig:            req.GetPluginConfig(),
+	}
+}
+
+func toProtoGenerateEnvelopeResponse(resp *plugin.GenerateEnvelopeResponse) (*pluginpb.GenerateEnvelopeResponse, error) {
+	return &pluginpb.GenerateEnvelopeResponse{
+		SignatureEnvelope:     resp.SignatureEnvelope,
+		SignatureEnvelopeType: resp.SignatureEnvelopeType,
+		Annotations:           resp.Annotations,
+	}, nil
+}
+
+func fromProtoGenerateEnvelopeResponse(resp *pluginpb.GenerateEnvelopeResponse) *plugin.GenerateEnvelopeResponse {
+	return &plugin.GenerateEnvelopeResponse{
+		SignatureEnvelope:     resp.GetSignatureEnvelope(),
+		SignatureEnvelopeType: resp.GetSignatureEnvelopeType(),
+		Annotations:           resp.GetAnnotations(),
+	}
+}
+
+func toProtoVerifySignatureRequest(req *plugin.VerifySignatureRequest) (*pluginpb.VerifySignatureRequest, error) {
+	attrs := req.Signature.CriticalAttributes
+	var extendedAttributes *structpb.Struct
+	if attrs.ExtendedAttributes != nil {
+		var err error
+		if extendedAttributes, err = structpb.NewStruct(attrs.ExtendedAttributes); err != nil {
+			return nil, fmt.Errorf("invalid extendedAttributes: %w", err)
+		}
+	}
+	signatureVerification := make([]string, len(req.TrustPolicy.SignatureVerification))
+	for i, c := range req.TrustPolicy.SignatureVerification {
+		signatureVerification[i] = string(c)
+	}
+
+	return &pluginpb.VerifySignatureRequest{
+		ContractVersion: req.ContractVersion,
+		Signature: &pluginpb.Signature{
+			CriticalAttributes: &pluginpb.CriticalAttributes{
+				ContentType:          attrs.ContentType,
+				SigningScheme:        attrs.SigningScheme,
+				Expiry:               toProtoTime(attrs.Expiry),
+				AuthenticSigningTime: toProtoTime(attrs.AuthenticSigningTime),
+				ExtendedAttributes:   extendedAttributes,
+			},
+			UnprocessedAttributes: req.Signature.UnprocessedAttributes,
+			CertificateChain:      req.Signature.CertificateChain,
+		},
+		TrustPolicy: &pluginpb.TrustPolicy{
+			TrustedIdentities:     req.TrustPolicy.TrustedIdentities,
+			SignatureVerification: signatureVerification,
+		},
+		PluginConfig: req.PluginConfig,
+	}, nil
+}
+
+func fromProtoVerifySignatureRequest(req *pluginpb.VerifySignatureRequest) *plugin.VerifySignatureRequest {
+	attrs := req.GetSignature().GetCriticalAttributes()
+	var extendedAttributes map[string]interface{}
+	if attrs.GetExtendedAttributes() != nil {
+		extendedAttributes = attrs.GetExtendedAttributes().AsMap()
+	}
+	var signatureVerification []plugin.Capability
+	for _, c := range req.GetTrustPolicy().GetSignatureVerification() {
+		signatureVerification = append(signatureVerification, plugin.Capability(c))
+	}
+
+	return &plugin.VerifySignatureRequest{
+		ContractVersion: req.GetContractVersion(),
+		Signature: plugin.Signature{
+			CriticalAttributes: plugin.CriticalAttributes{
+				ContentType:          attrs.GetContentType(),
+				SigningScheme:        attrs.GetSigningScheme(),
+				Expiry:               fromProtoTime(attrs.GetExpiry()),
+				AuthenticSigningTime: fromProtoTime(attrs.GetAuthenticSigningTime()),
+				ExtendedAttributes:   extendedAttributes,
+			},
+			UnprocessedAttributes: req.GetSignature().GetUnprocessedAttributes(),
+			CertificateChain:      req.GetSignature().GetCertificateChain(),
+		},
+		TrustPolicy: plugin.TrustPolicy{
+			TrustedIdentities:     req.GetTrustPolicy().GetTrustedIdentities(),
+			SignatureVerification: signatureVerification,
+		},
+		PluginConfig: req.GetPluginConfig(),
+	}
+}
+
+func toProtoVerifySignatureResponse(resp *plugin.VerifySignatureResponse) (*pluginpb.VerifySignatureResponse, error) {
+	results := make(map[string]*pluginpb.VerificationResult, len(resp.VerificationResults))
+	for c, r := range resp.VerificationResults {
+		results[string(c)] = &pluginpb.VerificationResult{Success: r.Success, Reason: r.Reason}
+	}
+	processedAttributes := make([]*structpb.Value, len(resp.ProcessedAttributes))
+	for i, attr := range resp.ProcessedAttributes {
+		v, err := structpb.NewValue(attr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid processedAttributes: %w", err)
+		}
+		processedAttributes[i] = v
+	}
+
+	return &pluginpb.VerifySignatureResponse{
+		VerificationResults: results,
+		ProcessedAttributes: processedAttributes,
+	}, nil
+}
+
+func fromProtoVerifySignatureResponse(resp *pluginpb.VerifySignatureResponse) *plugin.VerifySignatureResponse {
+	results := make(map[plugin.Capability]*plugin.VerificationResult, len(resp.GetVerificationResults()))
+	for c, r := range resp.GetVerificationResults() {
+		results[plugin.Capability(c)] = &plugin.VerificationResult{Success: r.GetSuccess(), Reason: r.GetReason()}
+	}
+	processedAttributes := make([]interface{}, len(resp.GetProcessedAttributes()))
+	for i, attr := range resp.GetProcessedAttributes() {
+		processedAttributes[i] = attr.AsInterface()
+	}
+
+	return &plugin.VerifySignatureResponse{
+		VerificationResults: results,
+		ProcessedAttributes: processedAttributes,
+	}
+}
+
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func fromProtoTime(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.AsTime()
+	return &v
+}
@@ -0,0 +1,92 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/grpcplugin/pluginpb"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorCodes maps plugin error codes to gRPC status codes.
+var errorCodes = map[plugin.ErrorCode]codes.Code{
+	plugin.ErrorCodeValidation:                 codes.InvalidArgument,
+	plugin.ErrorCodeUnsupportedContractVersion: codes.FailedPrecondition,
+	plugin.ErrorCodeAccessDenied:               codes.PermissionDenied,
+	plugin.ErrorCodeTimeout:                    codes.DeadlineExceeded,
+	plugin.ErrorCodeThrottled:                  codes.ResourceExhausted,
+	plugin.ErrorCodeGeneric:                    codes.Unknown,
+}
+
+// statusCodes maps gRPC status codes to plugin error codes, for the statuses without plugin error detail, e.g.
+// returned by the gRPC runtime.
+var statusCodes = map[codes.Code]plugin.ErrorCode{
+	codes.InvalidArgument:    plugin.ErrorCodeValidation,
+	codes.FailedPrecondition: plugin.ErrorCodeUnsupportedContractVersion,
+	codes.PermissionDenied:   plugin.ErrorCodeAccessDenied,
+	codes.Unauthenticated:    plugin.ErrorCodeAccessDenied,
+	codes.DeadlineExceeded:   plugin.ErrorCodeTimeout,
+	codes.Canceled:           plugin.ErrorCodeTimeout,
+	codes.ResourceExhausted:  plugin.ErrorCodeThrottled,
+}
+
+// toStatusError converts given error into a gRPC status error, with the plugin error set as status detail.
+// Errors other than plugin.Error are converted into generic plugin errors.
+func toStatusError(err error) error {
+	var plErr *plugin.Error
+	if !errors.As(err, &plErr) {
+		plErr = plugin.NewGenericError(err.Error())
+	}
+
+	code, ok := errorCodes[plErr.ErrCode]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, plErr.Message)
+	if withDetails, err := st.WithDetails(&pluginpb.Error{
+		ErrorCode:     string(plErr.ErrCode),
+		ErrorMessage:  plErr.Message,
+		ErrorMetadata: plErr.Metadata,
+	}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// fromStatusError converts given gRPC status error into a plugin error, using the plugin error set as status detail
+// if any.
+func fromStatusError(err error) *plugin.Error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return plugin.NewGenericError(err.Error())
+	}
+	for _, detail := range st.Details() {
+		if e, ok := detail.(*pluginpb.Error); ok {
+			return &plugin.Error{
+				ErrCode:  plugin.ErrorCode(e.GetErrorCode()),
+				Message:  e.GetErrorMessage(),
+				Metadata: e.GetErrorMetadata(),
+			}
+		}
+	}
+
+	code, ok := statusCodes[st.Code()]
+	if !ok {
+		code = plugin.ErrorCodeGeneric
+	}
+	return plugin.NewError(code, st.Message())
+}
@@ -0,0 +1,58 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	plErr := &plugin.Error{ErrCode: plugin.ErrorCodeThrottled, Message: "slow down", Metadata: map[string]string{"retryAfter": "5s"}}
+	err := toStatusError(plErr)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("expected status code %s but got %s", codes.ResourceExhausted, code)
+	}
+	if actual := fromStatusError(err); !reflect.DeepEqual(actual, plErr) {
+		t.Errorf("expected error %+v but got %+v", plErr, actual)
+	}
+
+	if code := status.Code(toStatusError(errors.New("some error"))); code != codes.Unknown {
+		t.Errorf("expected status code %s but got %s", codes.Unknown, code)
+	}
+}
+
+func TestFromStatusError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected *plugin.Error
+	}{
+		"deadlineExceeded": {err: status.Error(codes.DeadlineExceeded, "deadline"), expected: plugin.NewError(plugin.ErrorCodeTimeout, "deadline")},
+		"unauthenticated":  {err: status.Error(codes.Unauthenticated, "denied"), expected: plugin.NewError(plugin.ErrorCodeAccessDenied, "denied")},
+		"unavailable":      {err: status.Error(codes.Unavailable, "unavailable"), expected: plugin.NewGenericError("unavailable")},
+		"notStatus":        {err: errors.New("some error"), expected: plugin.NewGenericError("some error")},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := fromStatusError(test.err); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected error %+v but got %+v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+	"github.com/notaryproject/notation-plugin-framework-go/grpcplugin/pluginpb"
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestClientServer(t *testing.T) {
+	ctx := context.Background()
+	pl := mock.NewSigGeneratorPlugin(false)
+	c := newClient(t, pl)
+
+	mdReq := &plugin.GetMetadataRequest{PluginConfig: map[string]string{"key": "value"}}
+	assertRoundTrip(t, ctx, mdReq, pl.GetMetadata, c.GetMetadata)
+
+	dkReq := &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "someKeyId"}
+	assertRoundTrip(t, ctx, dkReq, pl.DescribeKey, c.DescribeKey)
+
+	gsReq := &plugin.GenerateSignatureRequest{
+		ContractVersion: plugin.ContractVersion,
+		KeyID:           "someKeyId",
+		KeySpec:         plugin.KeySpecRSA2048,
+		Hash:            plugin.HashAlgorithmSHA256,
+		Payload:         []byte("payload"),
+	}
+	assertRoundTrip(t, ctx, gsReq, pl.GenerateSignature, c.GenerateSignature)
+
+	now := time.Now().UTC()
+	vsReq := &plugin.VerifySignatureRequest{
+		ContractVersion: plugin.ContractVersion,
+		Signature: plugin.Signature{
+			CriticalAttributes: plugin.CriticalAttributes{
+				ContentType:          "someCT",
+				SigningScheme:        "someSigningScheme",
+				Expiry:               &now,
+				AuthenticSigningTime: &now,
+				ExtendedAttributes:   map[string]interface{}{"attr": "value", "nested": map[string]interface{}{"n": 1.5}},
+			},
+			UnprocessedAttributes: []string{"attr"},
+			CertificateChain:      [][]byte{[]byte("zap"), []byte("zop")},
+		},
+		TrustPolicy: plugin.TrustPolicy{
+			TrustedIdentities:     []string{"x509.subject:CN=example"},
+			SignatureVerification: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier, plugin.CapabilityRevocationCheckVerifier},
+		},
+	}
+	assertRoundTrip(t, ctx, vsReq, pl.VerifySignature, c.VerifySignature)
+
+	envPl := mock.NewPlugin(false)
+	envClient := newClient(t, envPl)
+	geReq := &plugin.GenerateEnvelopeRequest{
+		ContractVersion:       plugin.ContractVersion,
+		KeyID:                 "someKeyId",
+		PayloadType:           "application/vnd.cncf.notary.payload.v1+json",
+		SignatureEnvelopeType: "application/jose+json",
+		Payload:               []byte("payload"),
+	}
+	assertRoundTrip(t, ctx, geReq, envPl.GenerateEnvelope, envClient.GenerateEnvelope)
+}
+
+func TestConvertVerifySignatureRequest(t *testing.T) {
+	now := time.Now().UTC()
+	req := &plugin.VerifySignatureRequest{
+		ContractVersion: plugin.ContractVersion,
+		Signature: plugin.Signature{
+			CriticalAttributes: plugin.CriticalAttributes{
+				ContentType:        "someCT",
+				SigningScheme:      "someSigningScheme",
+				Expiry:             &now,
+				ExtendedAttributes: map[string]interface{}{"attr": []interface{}{"a", true}},
+			},
+			CertificateChain: [][]byte{[]byte("zap")},
+		},
+		TrustPolicy: plugin.TrustPolicy{
+			SignatureVerification: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier},
+		},
+		PluginConfig: map[string]string{"key": "value"},
+	}
+	pReq, err := toProtoVerifySignatureRequest(req)
+	if err != nil {
+		t.Fatalf("toProtoVerifySignatureRequest() failed with error: %v", err)
+	}
+	if actual := fromProtoVerifySignatureRequest(pReq); !reflect.DeepEqual(actual, req) {
+		t.Errorf("expected request %+v but got %+v", req, actual)
+	}
+
+	req.Signature.CriticalAttributes.ExtendedAttributes = map[string]interface{}{"attr": make(chan int)}
+	if _, err := toProtoVerifySignatureRequest(req); err == nil {
+		t.Error("toProtoVerifySignatureRequest() expected error for invalid extendedAttributes")
+	}
+}
+
+func TestClientServerError(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, mock.NewSigGeneratorPlugin(false))
+	tests := map[string]struct {
+		call     func() error
+		expected *plugin.Error
+	}{
+		"invalidRequest": {
+			call: func() error {
+				_, err := c.DescribeKey(ctx, &plugin.DescribeKeyRequest{KeyID: "someKeyId"})
+				return err
+			},
+			expected: plugin.NewValidationError("Input is not a valid JSON: contractVersion cannot be empty"),
+		},
+		"unsupportedContractVersion": {
+			call: func() error {
+				_, err := c.DescribeKey(ctx, &plugin.DescribeKeyRequest{ContractVersion: "2.0", KeyID: "someKeyId"})
+				return err
+			},
+			expected: plugin.NewUnsupportedContractVersionError("2.0"),
+		},
+		"pluginError": {
+			call: func() error {
+				_, err := newClient(t, &failingPlugin{mock.NewSigGeneratorPlugin(false)}).DescribeKey(ctx, &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "someKeyId"})
+				return err
+			},
+			expected: plugin.NewGenericError("DescribeKey() expected error"),
+		},
+		"invalidResponse": {
+			call: func() error {
+				_, err := c.DescribeKey(ctx, &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "otherKeyId"})
+				return err
+			},
+			expected: plugin.NewGenericError("Failed to generate response. Error: keyId \"someKeyId\" doesn't match the requested keyId \"otherKeyId\""),
+		},
+		"unimplemented": {
+			call: func() error {
+				_, err := c.GenerateEnvelope(ctx, &plugin.GenerateEnvelopeRequest{})
+				return err
+			},
+			expected: plugin.NewGenericError("method GenerateEnvelope not implemented"),
+		},
+		"panic": {
+			call: func() error {
+				_, err := newClient(t, &panicPlugin{mock.NewSigGeneratorPlugin(false)}).DescribeKey(ctx, &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "someKeyId"})
+				return err
+			},
+			expected: plugin.NewGenericError("plugin panicked: describe key panic"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.call()
+			var plErr *plugin.Error
+			if !errors.As(err, &plErr) || !reflect.DeepEqual(plErr, test.expected) {
+				t.Errorf("expected error %v but got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestServerInterceptors(t *testing.T) {
+	var intercepted []plugin.Command
+	interceptor := func(ctx context.Context, command plugin.Command, req plugin.Request, next cli.Handler) (any, error) {
+		intercepted = append(intercepted, command)
+		return next(ctx, req)
+	}
+	srv, err := NewServer(mock.NewSigGeneratorPlugin(false), cli.WithInterceptors(interceptor))
+	if err != nil {
+		t.Fatalf("NewServer() failed with error: %v", err)
+	}
+	c := newClientWithServer(t, srv)
+	if _, err := c.DescribeKey(context.Background(), &plugin.DescribeKeyRequest{ContractVersion: plugin.ContractVersion, KeyID: "someKeyId"}); err != nil {
+		t.Fatalf("DescribeKey() failed with error: %v", err)
+	}
+	if expected := []plugin.Command{plugin.CommandDescribeKey}; !reflect.DeepEqual(intercepted, expected) {
+		t.Errorf("expected intercepted commands %v but got %v", expected, intercepted)
+	}
+}
+
+func TestNewServerError(t *testing.T) {
+	if _, err := NewServer(nil); err == nil {
+		t.Error("NewServer() expected error for nil plugin")
+	}
+	if _, err := NewServer(mock.NewSigGeneratorPlugin(true)); err == nil {
+		t.Error("NewServer() expected error for failing metadata")
+	}
+}
+
+func newClient(t *testing.T, pl plugin.GenericPlugin) *Client {
+	t.Helper()
+	srv, err := NewServer(pl)
+	if err != nil {
+		t.Fatalf("NewServer() failed with error: %v", err)
+	}
+	return newClientWithServer(t, srv)
+}
+
+func newClientWithServer(t *testing.T, srv *Server) *Client {
+	t.Helper()
+	l := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pluginpb.RegisterPluginServer(s, srv)
+	go func() {
+		_ = s.Serve(l)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+// assertRoundTrip checks that calling the plugin through gRPC returns the same response as calling it directly.
+func assertRoundTrip[Req, Resp any](t *testing.T, ctx context.Context, req Req, direct, remote func(context.Context, Req) (Resp, error)) {
+	t.Helper()
+	expected, err := direct(ctx, req)
+	if err != nil {
+		t.Fatalf("%T: direct call failed with error: %v", req, err)
+	}
+	actual, err := remote(ctx, req)
+	if err != nil {
+		t.Fatalf("%T: gRPC call failed with error: %v", req, err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%T: expected response %+v but got %+v", req, expected, actual)
+	}
+}
+
+type failingPlugin struct {
+	plugin.Plugin
+}
+
+func (p *failingPlugin) DescribeKey(_ context.Context, _ *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
+	return nil, errors.New("DescribeKey() expected error")
+}
+
+type panicPlugin struct {
+	plugin.Plugin
+}
+
+func (p *panicPlugin) DescribeKey(_ context.Context, _ *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
+	panic("describe key panic")
+}
@@ -0,0 +1,280 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pluginpb/plugin.proto
+
+package pluginpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Plugin_GetMetadata_FullMethodName       = "/notation.plugin.v1.Plugin/GetMetadata"
+	Plugin_DescribeKey_FullMethodName       = "/notation.plugin.v1.Plugin/DescribeKey"
+	Plugin_GenerateSignature_FullMethodName = "/notation.plugin.v1.Plugin/GenerateSignature"
+	Plugin_GenerateEnvelope_FullMethodName  = "/notation.plugin.v1.Plugin/GenerateEnvelope"
+	Plugin_VerifySignature_FullMethodName   = "/notation.plugin.v1.Plugin/VerifySignature"
+)
+
+// PluginClient is the client API for Plugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PluginClient interface {
+	// GetMetadata mirrors the get-plugin-metadata command.
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	// DescribeKey mirrors the describe-key command.
+	DescribeKey(ctx context.Context, in *DescribeKeyRequest, opts ...grpc.CallOption) (*DescribeKeyResponse, error)
+	// GenerateSignature mirrors the generate-signature command.
+	GenerateSignature(ctx context.Context, in *GenerateSignatureRequest, opts ...grpc.CallOption) (*GenerateSignatureResponse, error)
+	// GenerateEnvelope mirrors the generate-envelope command.
+	GenerateEnvelope(ctx context.Context, in *GenerateEnvelopeRequest, opts ...grpc.CallOption) (*GenerateEnvelopeResponse, error)
+	// VerifySignature mirrors the verify-signature command.
+	VerifySignature(ctx context.Context, in *VerifySignatureRequest, opts ...grpc.CallOption) (*VerifySignatureResponse, error)
+}
+
+type pluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPluginClient(cc grpc.ClientConnInterface) PluginClient {
+	return &pluginClient{cc}
+}
+
+func (c *pluginClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, Plugin_GetMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) DescribeKey(ctx context.Context, in *DescribeKeyRequest, opts ...grpc.CallOption) (*DescribeKeyResponse, error) {
+	out := new(DescribeKeyResponse)
+	err := c.cc.Invoke(ctx, Plugin_DescribeKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) GenerateSignature(ctx context.Context, in *GenerateSignatureRequest, opts ...grpc.CallOption) (*GenerateSignatureResponse, error) {
+	out := new(GenerateSignatureResponse)
+	err := c.cc.Invoke(ctx, Plugin_GenerateSignature_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) GenerateEnvelope(ctx context.Context, in *GenerateEnvelopeRequest, opts ...grpc.CallOption) (*GenerateEnvelopeResponse, error) {
+	out := new(GenerateEnvelopeResponse)
+	err := c.cc.Invoke(ctx, Plugin_GenerateEnvelope_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) VerifySignature(ctx context.Context, in *VerifySignatureRequest, opts ...grpc.CallOption) (*VerifySignatureResponse, error) {
+	out := new(VerifySignatureResponse)
+	err := c.cc.Invoke(ctx, Plugin_VerifySignature_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginServer is the server API for Plugin service.
+// All implementations must embed UnimplementedPluginServer
+// for forward compatibility
+type PluginServer interface {
+	// GetMetadata mirrors the get-plugin-metadata command.
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	// DescribeKey mirrors the describe-key command.
+	DescribeKey(context.Context, *DescribeKeyRequest) (*DescribeKeyResponse, error)
+	// GenerateSignature mirrors the generate-signature command.
+	GenerateSignature(context.Context, *GenerateSignatureRequest) (*GenerateSignatureResponse, error)
+	// GenerateEnvelope mirrors the generate-envelope command.
+	GenerateEnvelope(context.Context, *GenerateEnvelopeRequest) (*GenerateEnvelopeResponse, error)
+	// VerifySignature mirrors the verify-signature command.
+	VerifySignature(context.Context, *VerifySignatureRequest) (*VerifySignatureResponse, error)
+	mustEmbedUnimplementedPluginServer()
+}
+
+// UnimplementedPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedPluginServer struct {
+}
+
+func (UnimplementedPluginServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedPluginServer) DescribeKey(context.Context, *DescribeKeyRequest) (*DescribeKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeKey not implemented")
+}
+func (UnimplementedPluginServer) GenerateSignature(context.Context, *GenerateSignatureRequest) (*GenerateSignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateSignature not implemented")
+}
+func (UnimplementedPluginServer) GenerateEnvelope(context.Context, *GenerateEnvelopeRequest) (*GenerateEnvelopeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateEnvelope not implemented")
+}
+func (UnimplementedPluginServer) VerifySignature(context.Context, *VerifySignatureRequest) (*VerifySignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifySignature not implemented")
+}
+func (UnimplementedPluginServer) mustEmbedUnimplementedPluginServer() {}
+
+// UnsafePluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PluginServer will
+// result in compilation errors.
+type UnsafePluginServer interface {
+	mustEmbedUnimplementedPluginServer()
+}
+
+func RegisterPluginServer(s grpc.ServiceRegistrar, srv PluginServer) {
+	s.RegisterService(&Plugin_ServiceDesc, srv)
+}
+
+func _Plugin_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_DescribeKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).DescribeKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_DescribeKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).DescribeKey(ctx, req.(*DescribeKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_GenerateSignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateSignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).GenerateSignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_GenerateSignature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).GenerateSignature(ctx, req.(*GenerateSignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_GenerateEnvelope_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateEnvelopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).GenerateEnvelope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_GenerateEnvelope_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).GenerateEnvelope(ctx, req.(*GenerateEnvelopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_VerifySignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifySignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).VerifySignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_VerifySignature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).VerifySignature(ctx, req.(*VerifySignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Plugin_ServiceDesc is the grpc.ServiceDesc for Plugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Plugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notation.plugin.v1.Plugin",
+	HandlerType: (*PluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler:    _Plugin_GetMetadata_Handler,
+		},
+		{
+			MethodName: "DescribeKey",
+			Handler:    _Plugin_DescribeKey_Handler,
+		},
+		{
+			MethodName: "GenerateSignature",
+			Handler:    _Plugin_GenerateSignature_Handler,
+		},
+		{
+			MethodName: "GenerateEnvelope",
+			Handler:    _Plugin_GenerateEnvelope_Handler,
+		},
+		{
+			MethodName: "VerifySignature",
+			Handler:    _Plugin_VerifySignature_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pluginpb/plugin.proto",
+}
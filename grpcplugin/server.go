@@ -0,0 +1,115 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcplugin provides the gRPC binding of the plugin contract: a server adapter serving any plugin over
+// gRPC and a client implementing plugin.Plugin over a gRPC connection.
+// Plugin errors are set as the details of the gRPC status, so that their error code survives the hop.
+// The protobuf definitions are in the pluginpb package.
+package grpcplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+	"github.com/notaryproject/notation-plugin-framework-go/grpcplugin/pluginpb"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Server serves a plugin over gRPC.
+// Requests are executed through a cli.CLI, so that they go through the same validation, contract version check,
+// interceptors and timeout as with the plugin executable, and responses are validated against their requests.
+// Methods of capabilities the plugin metadata doesn't declare return codes.Unimplemented.
+type Server struct {
+	pluginpb.UnimplementedPluginServer
+	c  *cli.CLI
+	md *plugin.GetMetadataResponse
+}
+
+// NewServer creates a new Server serving given plugin, to be registered using pluginpb.RegisterPluginServer.
+// Given options configure the underlying cli.CLI, e.g. cli.WithInterceptors or cli.WithTimeout. As with cli.New,
+// the plugin metadata is fetched and validated, and an error is returned if it is invalid.
+func NewServer(pl plugin.GenericPlugin, opts ...cli.Option) (*Server, error) {
+	if pl == nil {
+		return nil, errors.New("plugin cannot be nil")
+	}
+	c, err := cli.New(pl, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// cli.New already validated the metadata, it is fetched again for the capabilities the methods check.
+	md, err := pl.GetMetadata(context.Background(), &plugin.GetMetadataRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &Server{c: c, md: md}, nil
+}
+
+// GetMetadata returns the plugin metadata.
+func (s *Server) GetMetadata(ctx context.Context, req *pluginpb.GetMetadataRequest) (*pluginpb.GetMetadataResponse, error) {
+	return call(ctx, s.c, plugin.CommandGetMetadata, fromProtoGetMetadataRequest(req), toProtoGetMetadataResponse)
+}
+
+// DescribeKey describes the given key.
+func (s *Server) DescribeKey(ctx context.Context, req *pluginpb.DescribeKeyRequest) (*pluginpb.DescribeKeyResponse, error) {
+	if !s.md.HasCapability(plugin.CapabilitySignatureGenerator) {
+		return s.UnimplementedPluginServer.DescribeKey(ctx, req)
+	}
+	return call(ctx, s.c, plugin.CommandDescribeKey, fromProtoDescribeKeyRequest(req), toProtoDescribeKeyResponse)
+}
+
+// GenerateSignature generates the raw signature of the given payload.
+func (s *Server) GenerateSignature(ctx context.Context, req *pluginpb.GenerateSignatureRequest) (*pluginpb.GenerateSignatureResponse, error) {
+	if !s.md.HasCapability(plugin.CapabilitySignatureGenerator) {
+		return s.UnimplementedPluginServer.GenerateSignature(ctx, req)
+	}
+	return call(ctx, s.c, plugin.CommandGenerateSignature, fromProtoGenerateSignatureRequest(req), toProtoGenerateSignatureResponse)
+}
+
+// GenerateEnvelope generates the signature envelope of the given payload.
+func (s *Server) GenerateEnvelope(ctx context.Context, req *pluginpb.GenerateEnvelopeRequest) (*pluginpb.GenerateEnvelopeResponse, error) {
+	if !s.md.HasCapability(plugin.CapabilityEnvelopeGenerator) {
+		return s.UnimplementedPluginServer.GenerateEnvelope(ctx, req)
+	}
+	return call(ctx, s.c, plugin.CommandGenerateEnvelope, fromProtoGenerateEnvelopeRequest(req), toProtoGenerateEnvelopeResponse)
+}
+
+// VerifySignature verifies the given signature.
+func (s *Server) VerifySignature(ctx context.Context, req *pluginpb.VerifySignatureRequest) (*pluginpb.VerifySignatureResponse, error) {
+	if !s.md.HasCapability(plugin.CapabilityTrustedIdentityVerifier) && !s.md.HasCapability(plugin.CapabilityRevocationCheckVerifier) {
+		return s.UnimplementedPluginServer.VerifySignature(ctx, req)
+	}
+	return call(ctx, s.c, plugin.CommandVerifySignature, fromProtoVerifySignatureRequest(req), toProtoVerifySignatureResponse)
+}
+
+// call executes given command with given request through given CLI and converts the response into its protobuf
+// counterpart. Errors are returned as gRPC status errors.
+func call[Req any, Resp any, PResp any](ctx context.Context, c *cli.CLI, command plugin.Command, req Req, toProto func(*Resp) (PResp, error)) (pResp PResp, err error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return pResp, toStatusError(plugin.NewValidationErrorf("%s: %s", plugin.ErrorMsgMalformedInput, err))
+	}
+	op, plErr := c.Call(ctx, command, data)
+	if plErr != nil {
+		return pResp, toStatusError(plErr)
+	}
+	var resp Resp
+	if err := json.Unmarshal(op, &resp); err != nil {
+		return pResp, toStatusError(plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, err.Error()))
+	}
+	if pResp, err = toProto(&resp); err != nil {
+		return pResp, toStatusError(plugin.NewGenericErrorf(plugin.ErrorMsgMalformedOutputFmt, err.Error()))
+	}
+	return pResp, nil
+}
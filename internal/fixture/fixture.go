@@ -0,0 +1,99 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fixture defines the format of the recorded plugin invocations shared by the cli and replay packages.
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Ext is the extension of fixture files.
+const Ext = ".json"
+
+// Fixture is a recorded plugin invocation.
+type Fixture struct {
+	// Name is the file name of the fixture, without extension. It isn't serialized.
+	Name string `json:"-"`
+
+	// Args are the arguments the plugin executable was invoked with. The first argument is the base name of the
+	// executable.
+	Args []string `json:"args"`
+
+	// Command is the plugin command of the invocation.
+	Command plugin.Command `json:"command"`
+
+	// Request is the raw JSON read from stdin.
+	Request json.RawMessage `json:"request"`
+
+	// Response is the JSON written to stdout, if the invocation succeeded.
+	Response json.RawMessage `json:"response,omitempty"`
+
+	// Error is the JSON error written to stderr, if the invocation failed.
+	Error json.RawMessage `json:"error,omitempty"`
+
+	// ExitCode is the exit code of the invocation.
+	ExitCode int `json:"exitCode"`
+}
+
+// Save writes the fixture to given directory, in a file named after the fixture.
+func (f *Fixture) Save(dir string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, f.Name+Ext), append(data, '\n'), 0600)
+}
+
+// Load reads the fixture at given path.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if len(f.Args) < 2 || f.Command == "" {
+		return nil, fmt.Errorf("invalid fixture %s: args and command are required", path)
+	}
+	f.Name = strings.TrimSuffix(filepath.Base(path), Ext)
+	return &f, nil
+}
+
+// LoadDir reads all the fixtures in given directory, sorted by name.
+func LoadDir(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		f, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
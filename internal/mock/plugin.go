@@ -68,7 +68,7 @@ func (p *mockPlugin) GenerateEnvelope(ctx context.Context, req *plugin.GenerateE
 		return nil, fmt.Errorf("GenerateEnvelope() expected error")
 	}
 	return &plugin.GenerateEnvelopeResponse{
-		SignatureEnvelope:     []byte(""),
+		SignatureEnvelope:     []byte("envelope"),
 		SignatureEnvelopeType: req.SignatureEnvelopeType,
 		Annotations:           map[string]string{"manifestAnntnKey1": "value1"},
 	}, nil
@@ -84,18 +84,25 @@ func (p *mockPlugin) VerifySignature(ctx context.Context, req *plugin.VerifySign
 		pAttrs[i] = upAttrs[i]
 	}
 
-	return &plugin.VerifySignatureResponse{
-		ProcessedAttributes: pAttrs,
-		VerificationResults: map[plugin.Capability]*plugin.VerificationResult{
-			plugin.CapabilityTrustedIdentityVerifier: {
+	results := make(map[plugin.Capability]*plugin.VerificationResult)
+	for _, capability := range req.TrustPolicy.SignatureVerification {
+		switch capability {
+		case plugin.CapabilityTrustedIdentityVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Valid trusted Identity",
-			},
-			plugin.CapabilityRevocationCheckVerifier: {
+			}
+		case plugin.CapabilityRevocationCheckVerifier:
+			results[capability] = &plugin.VerificationResult{
 				Success: true,
 				Reason:  "Not revoked",
-			},
-		},
+			}
+		}
+	}
+
+	return &plugin.VerifySignatureResponse{
+		ProcessedAttributes: pAttrs,
+		VerificationResults: results,
 	}, nil
 }
 
@@ -115,10 +122,11 @@ func (p *mockPlugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataReq
 	}
 
 	return &plugin.GetMetadataResponse{
-		Name:         "Example Plugin",
-		Description:  "This is an description of example plugin. 🍺",
-		URL:          "https://example.com/notation/plugin",
-		Version:      "1.0.0",
-		Capabilities: cap,
+		Name:                      "com.example.plugin",
+		Description:               "This is an description of example plugin. 🍺",
+		URL:                       "https://example.com/notation/plugin",
+		Version:                   "1.0.0",
+		SupportedContractVersions: []string{plugin.ContractVersion},
+		Capabilities:              cap,
 	}, nil
 }
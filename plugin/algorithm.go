@@ -13,6 +13,14 @@
 
 package plugin
 
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
 // KeySpec is type of the signing algorithm, including algorithm and size.
 type KeySpec string
 
@@ -54,3 +62,134 @@ const (
 	SignatureAlgorithmRSASSA_PSS_SHA384 SignatureAlgorithm = "RSASSA-PSS-SHA-384"
 	SignatureAlgorithmRSASSA_PSS_SHA512 SignatureAlgorithm = "RSASSA-PSS-SHA-512"
 )
+
+// keySpecSignatureAlgorithms maps each KeySpec to the signature algorithm it must
+// be used with.
+//
+// https://github.com/notaryproject/notaryproject/blob/main/specs/signature-specification.md#algorithm-selection
+var keySpecSignatureAlgorithms = map[KeySpec]SignatureAlgorithm{
+	KeySpecRSA2048: SignatureAlgorithmRSASSA_PSS_SHA256,
+	KeySpecRSA3072: SignatureAlgorithmRSASSA_PSS_SHA384,
+	KeySpecRSA4096: SignatureAlgorithmRSASSA_PSS_SHA512,
+	KeySpecEC256:   SignatureAlgorithmECDSA_SHA256,
+	KeySpecEC384:   SignatureAlgorithmECDSA_SHA384,
+	KeySpecEC521:   SignatureAlgorithmECDSA_SHA512,
+}
+
+// keySpecHashAlgorithms maps each KeySpec to the hash algorithm it must be used
+// with.
+//
+// https://github.com/notaryproject/notaryproject/blob/main/specs/signature-specification.md#algorithm-selection
+var keySpecHashAlgorithms = map[KeySpec]HashAlgorithm{
+	KeySpecRSA2048: HashAlgorithmSHA256,
+	KeySpecRSA3072: HashAlgorithmSHA384,
+	KeySpecRSA4096: HashAlgorithmSHA512,
+	KeySpecEC256:   HashAlgorithmSHA256,
+	KeySpecEC384:   HashAlgorithmSHA384,
+	KeySpecEC521:   HashAlgorithmSHA512,
+}
+
+// cryptoHashes maps each HashAlgorithm to its crypto.Hash.
+var cryptoHashes = map[HashAlgorithm]crypto.Hash{
+	HashAlgorithmSHA256: crypto.SHA256,
+	HashAlgorithmSHA384: crypto.SHA384,
+	HashAlgorithmSHA512: crypto.SHA512,
+}
+
+// SignatureAlgorithm returns the signature algorithm the KeySpec must be used
+// with, or an empty SignatureAlgorithm if the KeySpec isn't supported.
+func (k KeySpec) SignatureAlgorithm() SignatureAlgorithm {
+	return keySpecSignatureAlgorithms[k]
+}
+
+// HashAlgorithm returns the hash algorithm the KeySpec must be used with, or an
+// empty HashAlgorithm if the KeySpec isn't supported.
+func (k KeySpec) HashAlgorithm() HashAlgorithm {
+	return keySpecHashAlgorithms[k]
+}
+
+// Validate returns an error if the KeySpec isn't supported by notation.
+func (k KeySpec) Validate() error {
+	if _, ok := keySpecSignatureAlgorithms[k]; !ok {
+		return NewValidationErrorf("keySpec %q is not supported", k)
+	}
+	return nil
+}
+
+// CryptoHash returns the crypto.Hash of the HashAlgorithm, or 0 if the
+// HashAlgorithm isn't supported.
+func (h HashAlgorithm) CryptoHash() crypto.Hash {
+	return cryptoHashes[h]
+}
+
+// Validate returns an error if the HashAlgorithm isn't supported by notation.
+func (h HashAlgorithm) Validate() error {
+	if _, ok := cryptoHashes[h]; !ok {
+		return NewValidationErrorf("hashAlgorithm %q is not supported", h)
+	}
+	return nil
+}
+
+// Validate returns an error if the SignatureAlgorithm isn't supported by
+// notation.
+func (s SignatureAlgorithm) Validate() error {
+	for _, alg := range keySpecSignatureAlgorithms {
+		if alg == s {
+			return nil
+		}
+	}
+	return NewValidationErrorf("signingAlgorithm %q is not supported", s)
+}
+
+// KeySpecFromPublicKey returns the KeySpec of the given RSA or ECDSA public key.
+// It returns an error if the key is nil, or if the key type or size isn't
+// supported by notation.
+func KeySpecFromPublicKey(key crypto.PublicKey) (KeySpec, error) {
+	switch key := key.(type) {
+	case nil:
+		return "", NewGenericError("public key cannot be nil")
+	case *rsa.PublicKey:
+		if key == nil || key.N == nil {
+			return "", NewGenericError("RSA public key cannot be nil")
+		}
+		switch bits := key.N.BitLen(); bits {
+		case 2048:
+			return KeySpecRSA2048, nil
+		case 3072:
+			return KeySpecRSA3072, nil
+		case 4096:
+			return KeySpecRSA4096, nil
+		default:
+			return "", NewGenericErrorf("RSA key size %d is not supported", bits)
+		}
+	case *ecdsa.PublicKey:
+		if key == nil || key.Curve == nil {
+			return "", NewGenericError("EC public key cannot be nil")
+		}
+		switch curve := key.Curve; curve {
+		case elliptic.P256():
+			return KeySpecEC256, nil
+		case elliptic.P384():
+			return KeySpecEC384, nil
+		case elliptic.P521():
+			return KeySpecEC521, nil
+		default:
+			if params := curve.Params(); params != nil {
+				return "", NewGenericErrorf("EC curve %s is not supported", params.Name)
+			}
+			return "", NewGenericError("EC curve is not supported")
+		}
+	default:
+		return "", NewGenericErrorf("public key type %T is not supported", key)
+	}
+}
+
+// KeySpecFromCertificate returns the KeySpec of the public key of the given
+// certificate, e.g. the signing certificate returned in a
+// generate-signature response.
+func KeySpecFromCertificate(cert *x509.Certificate) (KeySpec, error) {
+	if cert == nil {
+		return "", NewGenericError("certificate cannot be nil")
+	}
+	return KeySpecFromPublicKey(cert.PublicKey)
+}
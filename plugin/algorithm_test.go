@@ -0,0 +1,190 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"testing"
+)
+
+func TestKeySpec_Algorithms(t *testing.T) {
+	testCases := []struct {
+		keySpec KeySpec
+		sigAlg  SignatureAlgorithm
+		hashAlg HashAlgorithm
+		hash    crypto.Hash
+	}{
+		{KeySpecRSA2048, SignatureAlgorithmRSASSA_PSS_SHA256, HashAlgorithmSHA256, crypto.SHA256},
+		{KeySpecRSA3072, SignatureAlgorithmRSASSA_PSS_SHA384, HashAlgorithmSHA384, crypto.SHA384},
+		{KeySpecRSA4096, SignatureAlgorithmRSASSA_PSS_SHA512, HashAlgorithmSHA512, crypto.SHA512},
+		{KeySpecEC256, SignatureAlgorithmECDSA_SHA256, HashAlgorithmSHA256, crypto.SHA256},
+		{KeySpecEC384, SignatureAlgorithmECDSA_SHA384, HashAlgorithmSHA384, crypto.SHA384},
+		{KeySpecEC521, SignatureAlgorithmECDSA_SHA512, HashAlgorithmSHA512, crypto.SHA512},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(string(testcase.keySpec), func(t *testing.T) {
+			if err := testcase.keySpec.Validate(); err != nil {
+				t.Errorf("KeySpec#Validate failed with error: %+v", err)
+			}
+			if alg := testcase.keySpec.SignatureAlgorithm(); alg != testcase.sigAlg {
+				t.Errorf("expected signature algorithm %q but got %q", testcase.sigAlg, alg)
+			}
+			if err := testcase.sigAlg.Validate(); err != nil {
+				t.Errorf("SignatureAlgorithm#Validate failed with error: %+v", err)
+			}
+			hashAlg := testcase.keySpec.HashAlgorithm()
+			if hashAlg != testcase.hashAlg {
+				t.Errorf("expected hash algorithm %q but got %q", testcase.hashAlg, hashAlg)
+			}
+			if err := hashAlg.Validate(); err != nil {
+				t.Errorf("HashAlgorithm#Validate failed with error: %+v", err)
+			}
+			if hash := hashAlg.CryptoHash(); hash != testcase.hash {
+				t.Errorf("expected crypto hash %v but got %v", testcase.hash, hash)
+			}
+		})
+	}
+}
+
+func TestAlgorithms_Unsupported(t *testing.T) {
+	keySpec := KeySpec("RSA-1024")
+	if alg := keySpec.SignatureAlgorithm(); alg != "" {
+		t.Errorf("expected empty signature algorithm but got %q", alg)
+	}
+	if alg := keySpec.HashAlgorithm(); alg != "" {
+		t.Errorf("expected empty hash algorithm but got %q", alg)
+	}
+	if hash := HashAlgorithm("MD5").CryptoHash(); hash != 0 {
+		t.Errorf("expected zero crypto hash but got %v", hash)
+	}
+
+	testCases := map[string]struct {
+		err    error
+		expMsg string
+	}{
+		"keySpec": {
+			err:    keySpec.Validate(),
+			expMsg: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"keySpec \\\"RSA-1024\\\" is not supported\"}",
+		},
+		"hashAlgorithm": {
+			err:    HashAlgorithm("MD5").Validate(),
+			expMsg: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"hashAlgorithm \\\"MD5\\\" is not supported\"}",
+		},
+		"signingAlgorithm": {
+			err:    SignatureAlgorithm("RSASSA-PKCS1-SHA-256").Validate(),
+			expMsg: "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"signingAlgorithm \\\"RSASSA-PKCS1-SHA-256\\\" is not supported\"}",
+		},
+	}
+	for name, testcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if testcase.err == nil {
+				t.Fatal("Validate didn't returned error")
+			}
+			if testcase.err.Error() != testcase.expMsg {
+				t.Errorf("expected error message '%s' but got '%s'", testcase.expMsg, testcase.err.Error())
+			}
+		})
+	}
+}
+
+func TestKeySpecFromPublicKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     crypto.PublicKey
+		keySpec KeySpec
+	}{
+		{name: "RSA-2048", key: rsaPublicKey(2048), keySpec: KeySpecRSA2048},
+		{name: "RSA-3072", key: rsaPublicKey(3072), keySpec: KeySpecRSA3072},
+		{name: "RSA-4096", key: rsaPublicKey(4096), keySpec: KeySpecRSA4096},
+		{name: "EC-256", key: &ecdsa.PublicKey{Curve: elliptic.P256()}, keySpec: KeySpecEC256},
+		{name: "EC-384", key: &ecdsa.PublicKey{Curve: elliptic.P384()}, keySpec: KeySpecEC384},
+		{name: "EC-521", key: &ecdsa.PublicKey{Curve: elliptic.P521()}, keySpec: KeySpecEC521},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			keySpec, err := KeySpecFromPublicKey(testcase.key)
+			if err != nil {
+				t.Fatalf("KeySpecFromPublicKey failed with error: %+v", err)
+			}
+			if keySpec != testcase.keySpec {
+				t.Errorf("expected key spec %q but got %q", testcase.keySpec, keySpec)
+			}
+
+			keySpec, err = KeySpecFromCertificate(&x509.Certificate{PublicKey: testcase.key})
+			if err != nil {
+				t.Fatalf("KeySpecFromCertificate failed with error: %+v", err)
+			}
+			if keySpec != testcase.keySpec {
+				t.Errorf("expected key spec %q but got %q", testcase.keySpec, keySpec)
+			}
+		})
+	}
+}
+
+func TestKeySpecFromPublicKey_Error(t *testing.T) {
+	testCases := []struct {
+		name   string
+		key    crypto.PublicKey
+		expMsg string
+	}{
+		{name: "rsaKeySize", key: rsaPublicKey(1024), expMsg: "RSA key size 1024 is not supported"},
+		{name: "ecCurve", key: &ecdsa.PublicKey{Curve: elliptic.P224()}, expMsg: "EC curve P-224 is not supported"},
+		{name: "keyType", key: ed25519.PublicKey{}, expMsg: "public key type ed25519.PublicKey is not supported"},
+		{name: "nilKey", key: nil, expMsg: "public key cannot be nil"},
+		{name: "nilRSAKey", key: (*rsa.PublicKey)(nil), expMsg: "RSA public key cannot be nil"},
+		{name: "nilRSAModulus", key: &rsa.PublicKey{E: 65537}, expMsg: "RSA public key cannot be nil"},
+		{name: "nilECKey", key: (*ecdsa.PublicKey)(nil), expMsg: "EC public key cannot be nil"},
+		{name: "nilECCurve", key: &ecdsa.PublicKey{}, expMsg: "EC public key cannot be nil"},
+		{name: "ecCurveWithoutParams", key: &ecdsa.PublicKey{Curve: paramlessCurve{elliptic.P256()}}, expMsg: "EC curve is not supported"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			_, err := KeySpecFromPublicKey(testcase.key)
+			if err == nil {
+				t.Fatal("KeySpecFromPublicKey didn't returned error")
+			}
+			expMsg := "{\"errorCode\":\"ERROR\",\"errorMessage\":\"" + testcase.expMsg + "\"}"
+			if err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%s'", expMsg, err.Error())
+			}
+		})
+	}
+
+	if _, err := KeySpecFromCertificate(nil); err == nil {
+		t.Error("KeySpecFromCertificate didn't returned error for nil certificate")
+	}
+}
+
+// paramlessCurve is a custom elliptic curve without parameters.
+type paramlessCurve struct {
+	elliptic.Curve
+}
+
+func (paramlessCurve) Params() *elliptic.CurveParams {
+	return nil
+}
+
+// rsaPublicKey returns an RSA public key whose modulus has the given size.
+func rsaPublicKey(bits int) *rsa.PublicKey {
+	return &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), uint(bits-1)), E: 65537}
+}
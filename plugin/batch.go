@@ -0,0 +1,78 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"errors"
+)
+
+// GenerateSignatureBatchRequest contains the generate-signature requests
+// passed in a generate-signature-batch request.
+type GenerateSignatureBatchRequest []*GenerateSignatureRequest
+
+func (GenerateSignatureBatchRequest) Command() Command {
+	return CommandGenerateSignatureBatch
+}
+
+// Validate validates GenerateSignatureBatchRequest. The requests of the batch
+// are validated individually, so that an invalid request only fails its own
+// result.
+func (r GenerateSignatureBatchRequest) Validate() error {
+	if len(r) == 0 {
+		return NewValidationError("batch cannot be empty")
+	}
+
+	return nil
+}
+
+// GenerateSignatureBatchResult is the result of a single request of a
+// generate-signature-batch request. Exactly one of Response and Error is set.
+type GenerateSignatureBatchResult struct {
+	Response *GenerateSignatureResponse `json:"response,omitempty"`
+	Error    *Error                     `json:"error,omitempty"`
+}
+
+// GenerateSignatureBatchResponse is the response of a
+// generate-signature-batch request, containing one result per request in the
+// same order.
+type GenerateSignatureBatchResponse []GenerateSignatureBatchResult
+
+// Validate validates GenerateSignatureBatchResponse against the originating
+// GenerateSignatureBatchRequest.
+func (r GenerateSignatureBatchResponse) Validate(req GenerateSignatureBatchRequest) error {
+	if len(r) != len(req) {
+		return NewGenericErrorf("batch response contains %d results but %d were requested", len(r), len(req))
+	}
+
+	for i, result := range r {
+		if (result.Response == nil) == (result.Error == nil) {
+			return NewGenericErrorf("result %d must contain either a response or an error", i)
+		}
+		if result.Response == nil {
+			continue
+		}
+		if req[i] == nil {
+			return NewGenericErrorf("result %d must contain an error for an empty request", i)
+		}
+		if err := result.Response.Validate(req[i]); err != nil {
+			var plErr *Error
+			if errors.As(err, &plErr) {
+				return NewGenericErrorf("result %d: %s", i, plErr.Message)
+			}
+			return NewGenericErrorf("result %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
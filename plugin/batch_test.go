@@ -0,0 +1,88 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"testing"
+)
+
+func TestGenerateSignatureBatchRequest_Validate(t *testing.T) {
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(HashAlgorithmSHA384), []byte("zop"))
+	if err := (GenerateSignatureBatchRequest{&req, nil}).Validate(); err != nil {
+		t.Errorf("GenerateSignatureBatchRequest#Validate failed with error: %+v", err)
+	}
+
+	expMsg := "{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"batch cannot be empty\"}"
+	if err := (GenerateSignatureBatchRequest{}).Validate(); err == nil || err.Error() != expMsg {
+		t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+	}
+}
+
+func TestGenerateSignatureBatchRequest_Command(t *testing.T) {
+	if cmd := (GenerateSignatureBatchRequest{}).Command(); cmd != CommandGenerateSignatureBatch {
+		t.Errorf("GenerateSignatureBatchRequest#Command, expected %s but returned %s", CommandGenerateSignatureBatch, cmd)
+	}
+}
+
+func TestGenerateSignatureBatchResponse_Validate(t *testing.T) {
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(HashAlgorithmSHA384), []byte("zop"))
+	resp := &GenerateSignatureResponse{
+		KeyID:            "someKeyId",
+		Signature:        []byte("abcd"),
+		SigningAlgorithm: SignatureAlgorithmECDSA_SHA384,
+		CertificateChain: [][]byte{[]byte("abcd")},
+	}
+	batch := GenerateSignatureBatchRequest{&req, nil}
+	valid := GenerateSignatureBatchResponse{{Response: resp}, {Error: NewValidationError("request cannot be empty")}}
+	if err := valid.Validate(batch); err != nil {
+		t.Errorf("GenerateSignatureBatchResponse#Validate failed with error: %+v", err)
+	}
+
+	testCases := map[string]struct {
+		resp   GenerateSignatureBatchResponse
+		expMsg string
+	}{
+		"length": {
+			resp:   valid[:1],
+			expMsg: "batch response contains 1 results but 2 were requested",
+		},
+		"empty": {
+			resp:   GenerateSignatureBatchResponse{{}, valid[1]},
+			expMsg: "result 0 must contain either a response or an error",
+		},
+		"both": {
+			resp:   GenerateSignatureBatchResponse{{Response: resp, Error: NewGenericError("error")}, valid[1]},
+			expMsg: "result 0 must contain either a response or an error",
+		},
+		"emptyRequest": {
+			resp:   GenerateSignatureBatchResponse{valid[0], {Response: resp}},
+			expMsg: "result 1 must contain an error for an empty request",
+		},
+		"invalidResponse": {
+			resp:   GenerateSignatureBatchResponse{{Response: &GenerateSignatureResponse{KeyID: "otherKeyId"}}, valid[1]},
+			expMsg: "result 0: keyId \"otherKeyId\" doesn't match the requested keyId \"someKeyId\"",
+		},
+	}
+	for name, testcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.resp.Validate(batch)
+			if err == nil {
+				t.Fatal("GenerateSignatureBatchResponse#Validate didn't returned error")
+			}
+			if plErr, ok := err.(*Error); !ok || plErr.Message != testcase.expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", testcase.expMsg, err)
+			}
+		})
+	}
+}
@@ -46,3 +46,25 @@ type DescribeKeyResponse struct {
 	// https://github.com/notaryproject/notaryproject/blob/main/specs/signature-specification.md#algorithm-selection
 	KeySpec KeySpec `json:"keySpec"`
 }
+
+// Validate validates DescribeKeyResponse struct against the originating
+// DescribeKeyRequest.
+func (r *DescribeKeyResponse) Validate(req *DescribeKeyRequest) error {
+	if r == nil {
+		return NewGenericError("response cannot be empty")
+	}
+
+	if r.KeyID != req.KeyID {
+		return NewGenericErrorf("keyId %q doesn't match the requested keyId %q", r.KeyID, req.KeyID)
+	}
+
+	if r.KeySpec == "" {
+		return NewGenericError("keySpec cannot be empty")
+	}
+
+	if _, ok := keySpecSignatureAlgorithms[r.KeySpec]; !ok {
+		return NewGenericErrorf("keySpec %q is not supported", r.KeySpec)
+	}
+
+	return nil
+}
@@ -70,3 +70,35 @@ func getDescribeKeyRequest(cv, kid string) DescribeKeyRequest {
 		KeyID:           kid,
 	}
 }
+
+func TestDescribeKeyResponse_Validate(t *testing.T) {
+	req := getDescribeKeyRequest(ContractVersion, "someKeyId")
+	resp := &DescribeKeyResponse{KeyID: "someKeyId", KeySpec: KeySpecEC384}
+	if err := resp.Validate(&req); err != nil {
+		t.Errorf("DescribeKeyResponse#Validate failed with error: %+v", err)
+	}
+}
+
+func TestDescribeKeyResponse_Validate_Error(t *testing.T) {
+	req := getDescribeKeyRequest(ContractVersion, "someKeyId")
+	testCases := []struct {
+		name string
+		resp *DescribeKeyResponse
+		msg  string
+	}{
+		{name: "nil", resp: nil, msg: "response cannot be empty"},
+		{name: "keyId", resp: &DescribeKeyResponse{KeyID: "otherKeyId", KeySpec: KeySpecEC384}, msg: "keyId \\\"otherKeyId\\\" doesn't match the requested keyId \\\"someKeyId\\\""},
+		{name: "emptyKeySpec", resp: &DescribeKeyResponse{KeyID: "someKeyId"}, msg: "keySpec cannot be empty"},
+		{name: "unknownKeySpec", resp: &DescribeKeyResponse{KeyID: "someKeyId", KeySpec: "RSA-1024"}, msg: "keySpec \\\"RSA-1024\\\" is not supported"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate(&req)
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s\"}", testcase.msg)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
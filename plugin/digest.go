@@ -0,0 +1,103 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/asn1"
+	"math/big"
+)
+
+// ecSignatureSizes maps the EC key specs to the byte size of the r and s
+// values of their signatures.
+var ecSignatureSizes = map[KeySpec]int{
+	KeySpecEC256: 32,
+	KeySpecEC384: 48,
+	KeySpecEC521: 66,
+}
+
+// DigestSignatureGenerator implements the GenerateSignature method of a
+// SignatureGeneratorPlugin using a DigestSigner. It can be embedded in a
+// plugin whose backend signs pre-computed digests.
+type DigestSignatureGenerator struct {
+	signer DigestSigner
+}
+
+// NewDigestSignatureGenerator creates a DigestSignatureGenerator using the
+// given DigestSigner.
+func NewDigestSignatureGenerator(signer DigestSigner) *DigestSignatureGenerator {
+	return &DigestSignatureGenerator{signer: signer}
+}
+
+// GenerateSignature hashes the payload with the requested hash algorithm and
+// signs the digest using the DigestSigner. RSA keys are signed with
+// RSASSA-PSS using a salt length equal to the hash length, as required by the
+// notary project specification, and the ASN.1 DER signatures of EC keys are
+// converted to the IEEE P1363 r||s format expected by the JWS and COSE
+// signature envelopes.
+//
+// https://github.com/notaryproject/notaryproject/blob/main/specs/signature-specification.md#algorithm-selection
+func (g *DigestSignatureGenerator) GenerateSignature(ctx context.Context, req *GenerateSignatureRequest) (*GenerateSignatureResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	digest, hash, err := req.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts crypto.SignerOpts = hash
+	switch req.KeySpec {
+	case KeySpecRSA2048, KeySpecRSA3072, KeySpecRSA4096:
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+
+	signature, certChain, err := g.signer.SignDigest(ctx, req, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+	if size, ok := ecSignatureSizes[req.KeySpec]; ok {
+		if signature, err = ecdsaP1363Signature(signature, size); err != nil {
+			return nil, err
+		}
+	}
+
+	return &GenerateSignatureResponse{
+		KeyID:            req.KeyID,
+		Signature:        signature,
+		SigningAlgorithm: req.KeySpec.SignatureAlgorithm(),
+		CertificateChain: certChain,
+	}, nil
+}
+
+// ecdsaP1363Signature converts the given ASN.1 DER ECDSA signature into the
+// IEEE P1363 format, i.e. r||s with each value left-padded to the given size.
+func ecdsaP1363Signature(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) != 0 {
+		return nil, NewGenericError("ECDSA signature is not a valid ASN.1 DER signature")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > size*8 || sig.S.BitLen() > size*8 {
+		return nil, NewGenericError("ECDSA signature values are out of range")
+	}
+	p1363 := make([]byte, 2*size)
+	sig.R.FillBytes(p1363[:size])
+	sig.S.FillBytes(p1363[size:])
+	return p1363, nil
+}
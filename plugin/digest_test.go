@@ -0,0 +1,159 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// mockDigestSigner signs digests using a crypto.Signer, as a KMS or HSM
+// backend would.
+type mockDigestSigner struct {
+	signer crypto.Signer
+	err    error
+}
+
+func (s *mockDigestSigner) SignDigest(_ context.Context, _ *GenerateSignatureRequest, digest []byte, opts crypto.SignerOpts) ([]byte, [][]byte, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	sig, err := s.signer.Sign(rand.Reader, digest, opts)
+	return sig, mockCertChain, err
+}
+
+func TestDigestSignatureGenerator_GenerateSignature(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		signer  crypto.Signer
+		keySpec KeySpec
+		verify  func(digest, sig []byte) bool
+	}{
+		{
+			name:    "RSA",
+			signer:  rsaKey,
+			keySpec: KeySpecRSA2048,
+			verify: func(digest, sig []byte) bool {
+				opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+				return rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, digest, sig, opts) == nil
+			},
+		},
+		{
+			name:    "EC",
+			signer:  ecKey,
+			keySpec: KeySpecEC384,
+			verify: func(digest, sig []byte) bool {
+				// IEEE P1363 r||s with 48-byte values
+				if len(sig) != 96 {
+					return false
+				}
+				r, s := new(big.Int).SetBytes(sig[:48]), new(big.Int).SetBytes(sig[48:])
+				return ecdsa.Verify(&ecKey.PublicKey, digest, r, s)
+			},
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			generator := NewDigestSignatureGenerator(&mockDigestSigner{signer: testcase.signer})
+			req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(testcase.keySpec), string(testcase.keySpec.HashAlgorithm()), []byte("zop"))
+			resp, err := generator.GenerateSignature(context.Background(), &req)
+			if err != nil {
+				t.Fatalf("DigestSignatureGenerator#GenerateSignature failed with error: %+v", err)
+			}
+			if err := resp.Validate(&req); err != nil {
+				t.Errorf("GenerateSignatureResponse#Validate failed with error: %+v", err)
+			}
+			if !reflect.DeepEqual(resp.CertificateChain, mockCertChain) {
+				t.Errorf("expected certificate chain %v but got %v", mockCertChain, resp.CertificateChain)
+			}
+			digest, _, _ := req.Digest()
+			if !testcase.verify(digest, resp.Signature) {
+				t.Error("signature verification failed")
+			}
+		})
+	}
+}
+
+func TestDigestSignatureGenerator_GenerateSignature_Error(t *testing.T) {
+	signErr := NewError(ErrorCodeAccessDenied, "access denied")
+	generator := NewDigestSignatureGenerator(&mockDigestSigner{err: signErr})
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC256), string(HashAlgorithmSHA256), []byte("zop"))
+	if _, err := generator.GenerateSignature(context.Background(), &req); !errors.Is(err, signErr) {
+		t.Errorf("expected error %v but got %v", signErr, err)
+	}
+
+	req = getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC256), string(HashAlgorithmSHA512), []byte("zop"))
+	if _, err := generator.GenerateSignature(context.Background(), &req); err == nil {
+		t.Error("DigestSignatureGenerator#GenerateSignature didn't returned error for invalid request")
+	}
+
+	generator = NewDigestSignatureGenerator(&mockDigestSigner{signer: rawSigner{}})
+	req = getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC256), string(HashAlgorithmSHA256), []byte("zop"))
+	expMsg := "{\"errorCode\":\"ERROR\",\"errorMessage\":\"ECDSA signature is not a valid ASN.1 DER signature\"}"
+	if _, err := generator.GenerateSignature(context.Background(), &req); err == nil || err.Error() != expMsg {
+		t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+	}
+}
+
+func TestECDSAP1363Signature(t *testing.T) {
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{big.NewInt(1), big.NewInt(258)})
+	if err != nil {
+		t.Fatalf("failed to marshal signature: %v", err)
+	}
+	sig, err := ecdsaP1363Signature(der, 4)
+	if err != nil {
+		t.Fatalf("ecdsaP1363Signature failed with error: %+v", err)
+	}
+	if expected := []byte{0, 0, 0, 1, 0, 0, 1, 2}; !reflect.DeepEqual(sig, expected) {
+		t.Errorf("expected signature %v but got %v", expected, sig)
+	}
+
+	if _, err := ecdsaP1363Signature(der, 1); err == nil {
+		t.Error("ecdsaP1363Signature didn't returned error for values exceeding the size")
+	}
+	if _, err := ecdsaP1363Signature(append(der, 0), 4); err == nil {
+		t.Error("ecdsaP1363Signature didn't returned error for trailing data")
+	}
+}
+
+// rawSigner returns a fixed signature that isn't ASN.1 DER encoded.
+type rawSigner struct{}
+
+func (rawSigner) Public() crypto.PublicKey {
+	return nil
+}
+
+func (rawSigner) Sign(_ io.Reader, _ []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return make([]byte, 64), nil
+}
@@ -13,6 +13,20 @@
 
 package plugin
 
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
+)
+
+// nameRegex matches reverse-DNS-like plugin names such as "com.example.plugin", i.e. at least two dot-separated
+// segments.
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+([-_][a-zA-Z0-9]+)*(\.[a-zA-Z0-9]+([-_][a-zA-Z0-9]+)*)+$`)
+
+// semVerRegex matches semantic versions as defined at https://semver.org.
+var semVerRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
 // GetMetadataRequest contains the parameters passed in a get-plugin-metadata
 // request.
 type GetMetadataRequest struct {
@@ -38,6 +52,62 @@ type GetMetadataResponse struct {
 	Capabilities              []Capability `json:"capabilities"`
 }
 
+// Validate validates GetMetadataResponse struct.
+// The name must be a reverse-DNS-like name with at least two dot-separated
+// segments, such as "com.example.plugin", the version must be a semantic
+// version, the url must be an absolute URL, the capabilities must be known
+// capabilities and the supported contract versions must include
+// ContractVersion.
+func (resp *GetMetadataResponse) Validate() error {
+	if resp == nil {
+		return NewGenericError("response cannot be empty")
+	}
+
+	if resp.Name == "" {
+		return NewGenericError("name cannot be empty")
+	}
+
+	if !nameRegex.MatchString(resp.Name) {
+		return NewGenericErrorf("name %q is not valid, it must be a reverse-DNS-like name such as \"com.example.plugin\"", resp.Name)
+	}
+
+	if resp.Version == "" {
+		return NewGenericError("version cannot be empty")
+	}
+
+	if !semVerRegex.MatchString(resp.Version) {
+		return NewGenericErrorf("version %q is not a valid semantic version", resp.Version)
+	}
+
+	if resp.URL == "" {
+		return NewGenericError("url cannot be empty")
+	}
+
+	if u, err := url.Parse(resp.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return NewGenericErrorf("url %q is not a valid absolute URL", resp.URL)
+	}
+
+	if len(resp.SupportedContractVersions) == 0 {
+		return NewGenericError("supportedContractVersions cannot be empty")
+	}
+
+	if !slices.Contains(resp.SupportedContractVersions, ContractVersion) {
+		return NewGenericErrorf("supportedContractVersions must include %q", ContractVersion)
+	}
+
+	if len(resp.Capabilities) == 0 {
+		return NewGenericError("capabilities cannot be empty")
+	}
+
+	for _, capability := range resp.Capabilities {
+		if !slices.Contains(capabilities, capability) {
+			return NewGenericErrorf("capability %q is not supported", capability)
+		}
+	}
+
+	return nil
+}
+
 // HasCapability return true if the metadata states that the
 // capability is supported.
 // Returns true if capability is empty.
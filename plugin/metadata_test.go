@@ -14,6 +14,7 @@
 package plugin
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -41,6 +42,86 @@ func TestGetMetadataResponseHasCapability(t *testing.T) {
 	}
 }
 
+func TestGetMetadataResponse_Validate(t *testing.T) {
+	resps := []*GetMetadataResponse{
+		getMetadataResponse("com.example.plugin", "1.0.0", "https://example.com/notation/plugin", []Capability{CapabilitySignatureGenerator}),
+		getMetadataResponse("io.azure.key-vault", "1.2.0-beta.1+build.5", "http://example.com", []Capability{CapabilityEnvelopeGenerator, CapabilityTrustedIdentityVerifier}),
+	}
+
+	for _, resp := range resps {
+		if err := resp.Validate(); err != nil {
+			t.Errorf("GetMetadataResponse#Validate failed with error: %+v", err)
+		}
+	}
+}
+
+func TestGetMetadataResponse_Validate_Error(t *testing.T) {
+	testCases := []struct {
+		name string
+		resp *GetMetadataResponse
+	}{
+		{name: "response", resp: nil},
+		{name: "name", resp: getMetadataResponse("", "1.0.0", "https://example.com/notation/plugin", []Capability{CapabilitySignatureGenerator})},
+		{name: "version", resp: getMetadataResponse("com.example.plugin", "", "https://example.com/notation/plugin", []Capability{CapabilitySignatureGenerator})},
+		{name: "url", resp: getMetadataResponse("com.example.plugin", "1.0.0", "", []Capability{CapabilitySignatureGenerator})},
+		{name: "capabilities", resp: getMetadataResponse("com.example.plugin", "1.0.0", "https://example.com/notation/plugin", nil)},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate()
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s cannot be empty\"}", testcase.name)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
+
+func TestGetMetadataResponse_Validate_Invalid(t *testing.T) {
+	validResp := func() *GetMetadataResponse {
+		return getMetadataResponse("com.example.plugin", "1.0.0", "https://example.com/notation/plugin", []Capability{CapabilitySignatureGenerator})
+	}
+	invalidName := validResp()
+	invalidName.Name = "com example/plugin"
+	singleSegmentName := validResp()
+	singleSegmentName.Name = "azure-kv"
+	invalidVersion := validResp()
+	invalidVersion.Version = "v1.0"
+	invalidURL := validResp()
+	invalidURL.URL = "example.com/notation/plugin"
+	noContractVersions := validResp()
+	noContractVersions.SupportedContractVersions = nil
+	unsupportedContractVersions := validResp()
+	unsupportedContractVersions.SupportedContractVersions = []string{"2.0"}
+	unknownCapability := validResp()
+	unknownCapability.Capabilities = []Capability{"SIGNATURE_GENERATOR.UNKNOWN"}
+
+	testCases := []struct {
+		name string
+		resp *GetMetadataResponse
+		msg  string
+	}{
+		{name: "name", resp: invalidName, msg: "name \\\"com example/plugin\\\" is not valid, it must be a reverse-DNS-like name such as \\\"com.example.plugin\\\""},
+		{name: "singleSegmentName", resp: singleSegmentName, msg: "name \\\"azure-kv\\\" is not valid, it must be a reverse-DNS-like name such as \\\"com.example.plugin\\\""},
+		{name: "version", resp: invalidVersion, msg: "version \\\"v1.0\\\" is not a valid semantic version"},
+		{name: "url", resp: invalidURL, msg: "url \\\"example.com/notation/plugin\\\" is not a valid absolute URL"},
+		{name: "noContractVersions", resp: noContractVersions, msg: "supportedContractVersions cannot be empty"},
+		{name: "unsupportedContractVersions", resp: unsupportedContractVersions, msg: "supportedContractVersions must include \\\"1.0\\\""},
+		{name: "capability", resp: unknownCapability, msg: "capability \\\"SIGNATURE_GENERATOR.UNKNOWN\\\" is not supported"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate()
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s\"}", testcase.msg)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
+
 func TestGetMetadataRequest_Validate(t *testing.T) {
 	reqs := []GetMetadataRequest{
 		{},
@@ -60,3 +141,14 @@ func TestGetMetadataRequest_Command(t *testing.T) {
 		t.Errorf("DescribeKeyRequest#Command, expected %s but returned %s", CommandGetMetadata, cmd)
 	}
 }
+
+func getMetadataResponse(name, version, url string, caps []Capability) *GetMetadataResponse {
+	return &GetMetadataResponse{
+		Name:                      name,
+		Description:               "This is an description of example plugin",
+		Version:                   version,
+		URL:                       url,
+		SupportedContractVersions: []string{ContractVersion},
+		Capabilities:              caps,
+	}
+}
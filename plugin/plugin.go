@@ -14,10 +14,15 @@
 // Package plugin provides the tooling to use the notation plugin.
 //
 // includes a CLIManager and a CLIPlugin implementation.
+//
+// The methods of a plugin must return promptly once their context is done,
+// e.g. when the command times out, since the caller stops waiting for them
+// and a call ignoring its context keeps using the backend in the background.
 package plugin
 
 import (
 	"context"
+	"crypto"
 )
 
 // GenericPlugin is the base requirement to be a plugin.
@@ -26,8 +31,9 @@ type GenericPlugin interface {
 	GetMetadata(ctx context.Context, req *GetMetadataRequest) (*GetMetadataResponse, error)
 }
 
-// SignPlugin defines the required methods to be a SignPlugin.
-type SignPlugin interface {
+// SignatureGeneratorPlugin defines the required methods to be a plugin with
+// SIGNATURE_GENERATOR.RAW capability.
+type SignatureGeneratorPlugin interface {
 	GenericPlugin
 
 	// DescribeKey returns the KeySpec of a key.
@@ -35,13 +41,58 @@ type SignPlugin interface {
 
 	// GenerateSignature generates the raw signature based on the request.
 	GenerateSignature(ctx context.Context, req *GenerateSignatureRequest) (*GenerateSignatureResponse, error)
+}
+
+// BatchSigner is an optional interface a SignatureGeneratorPlugin can
+// implement to generate the raw signatures of a batch of requests at once,
+// e.g. using the native batch signing of its backend.
+type BatchSigner interface {
+	// GenerateSignatureBatch generates the raw signatures of the requests in
+	// the batch. The response must contain one result per request, in the
+	// same order.
+	GenerateSignatureBatch(ctx context.Context, req GenerateSignatureBatchRequest) (GenerateSignatureBatchResponse, error)
+}
+
+// HealthChecker is an optional interface a plugin can implement to report
+// whether its installation is healthy, e.g. whether its backend can be
+// reached using the given plugin config. It's used by diagnostic tools.
+type HealthChecker interface {
+	// CheckHealth returns an error describing the problem if the plugin
+	// isn't healthy.
+	CheckHealth(ctx context.Context, pluginConfig map[string]string) error
+}
+
+// DigestSigner signs pre-computed digests, as done by most KMS and HSM
+// backends. It can be turned into the GenerateSignature method of a
+// SignatureGeneratorPlugin using NewDigestSignatureGenerator.
+type DigestSigner interface {
+	// SignDigest signs the digest of the payload of the request using the
+	// requested key and returns the signature along with the certificate
+	// chain starting with the leaf certificate. The opts are the ones a
+	// crypto.Signer expects for the requested KeySpec, i.e. *rsa.PSSOptions
+	// for RSA keys and the crypto.Hash for EC keys, and the signature must be
+	// in the format a crypto.Signer returns, i.e. ASN.1 DER for EC keys.
+	SignDigest(ctx context.Context, req *GenerateSignatureRequest, digest []byte, opts crypto.SignerOpts) (signature []byte, certificateChain [][]byte, err error)
+}
+
+// EnvelopeGeneratorPlugin defines the required method to be a plugin with
+// SIGNATURE_GENERATOR.ENVELOPE capability.
+type EnvelopeGeneratorPlugin interface {
+	GenericPlugin
 
 	// GenerateEnvelope generates the Envelope with signature based on the
 	// request.
 	GenerateEnvelope(ctx context.Context, req *GenerateEnvelopeRequest) (*GenerateEnvelopeResponse, error)
 }
 
-// VerifyPlugin defines the required method to be a VerifyPlugin.
+// SignPlugin defines the required methods to be a SignPlugin.
+type SignPlugin interface {
+	SignatureGeneratorPlugin
+	EnvelopeGeneratorPlugin
+}
+
+// VerifyPlugin defines the required method to be a VerifyPlugin, i.e. a plugin
+// with any SIGNATURE_VERIFIER.* capability.
 type VerifyPlugin interface {
 	GenericPlugin
 
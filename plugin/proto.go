@@ -15,6 +15,8 @@
 // and notation external plugin.
 package plugin
 
+import "github.com/notaryproject/notation-plugin-framework-go/internal/slices"
+
 // BinaryPrefix is the prefix required on all plugin binary names.
 const BinaryPrefix = "notation-"
 
@@ -42,6 +44,30 @@ const (
 	CapabilityRevocationCheckVerifier Capability = "SIGNATURE_VERIFIER.REVOCATION_CHECK"
 )
 
+// capabilities contains all the capabilities available in the plugin contract.
+var capabilities = []Capability{
+	CapabilitySignatureGenerator,
+	CapabilityEnvelopeGenerator,
+	CapabilityTrustedIdentityVerifier,
+	CapabilityRevocationCheckVerifier,
+}
+
+// verifierCapabilities contains the capabilities that can be requested in the
+// trust policy of a verify-signature request.
+var verifierCapabilities = []Capability{
+	CapabilityTrustedIdentityVerifier,
+	CapabilityRevocationCheckVerifier,
+}
+
+// Validate returns an error if the Capability isn't available in the plugin
+// contract.
+func (c Capability) Validate() error {
+	if !slices.Contains(capabilities, c) {
+		return NewValidationErrorf("capability %q is not supported", c)
+	}
+	return nil
+}
+
 // Command is a CLI command available in the plugin contract.
 type Command string
 
@@ -77,5 +103,11 @@ const (
 	// any SIGNATURE_VERIFIER.* capability
 	CommandVerifySignature Command = "verify-signature"
 
+	// CommandGenerateSignatureBatch is the name of the vendor extension command
+	// which generates the raw signatures of a batch of generate-signature
+	// requests. It isn't part of the plugin contract and is only available
+	// when enabled by the plugin.
+	CommandGenerateSignatureBatch Command = "generate-signature-batch"
+
 	Version Command = "version"
 )
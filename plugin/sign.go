@@ -13,6 +13,12 @@
 
 package plugin
 
+import (
+	"crypto"
+	_ "crypto/sha256" // register crypto.SHA256
+	_ "crypto/sha512" // register crypto.SHA384 and crypto.SHA512
+)
+
 // GenerateSignatureRequest contains the parameters passed in a
 // generate-signature request.
 type GenerateSignatureRequest struct {
@@ -46,6 +52,18 @@ func (r GenerateSignatureRequest) Validate() error {
 		return NewValidationError("hashAlgorithm cannot be empty")
 	}
 
+	if err := r.KeySpec.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Hash.Validate(); err != nil {
+		return err
+	}
+
+	if expectedHash := r.KeySpec.HashAlgorithm(); r.Hash != expectedHash {
+		return NewValidationErrorf("hashAlgorithm %q doesn't match the keySpec %q, expected %q", r.Hash, r.KeySpec, expectedHash)
+	}
+
 	if len(r.Payload) == 0 {
 		return NewValidationError("payload cannot be empty")
 	}
@@ -53,6 +71,19 @@ func (r GenerateSignatureRequest) Validate() error {
 	return nil
 }
 
+// Digest hashes the payload with the requested hash algorithm and returns the
+// digest along with its crypto.Hash, for backends signing a pre-computed
+// digest rather than the payload.
+func (r GenerateSignatureRequest) Digest() ([]byte, crypto.Hash, error) {
+	if err := r.Hash.Validate(); err != nil {
+		return nil, 0, err
+	}
+	hash := r.Hash.CryptoHash()
+	h := hash.New()
+	h.Write(r.Payload)
+	return h.Sum(nil), hash, nil
+}
+
 // GenerateSignatureResponse is the response of a generate-signature request.
 type GenerateSignatureResponse struct {
 	KeyID            string             `json:"keyId"`
@@ -64,6 +95,36 @@ type GenerateSignatureResponse struct {
 	CertificateChain [][]byte `json:"certificateChain"`
 }
 
+// Validate validates GenerateSignatureResponse struct against the originating
+// GenerateSignatureRequest.
+func (r *GenerateSignatureResponse) Validate(req *GenerateSignatureRequest) error {
+	if r == nil {
+		return NewGenericError("response cannot be empty")
+	}
+
+	if r.KeyID != req.KeyID {
+		return NewGenericErrorf("keyId %q doesn't match the requested keyId %q", r.KeyID, req.KeyID)
+	}
+
+	if len(r.Signature) == 0 {
+		return NewGenericError("signature cannot be empty")
+	}
+
+	expectedAlg := req.KeySpec.SignatureAlgorithm()
+	if expectedAlg == "" {
+		return NewGenericErrorf("keySpec %q is not supported", req.KeySpec)
+	}
+	if r.SigningAlgorithm != expectedAlg {
+		return NewGenericErrorf("signingAlgorithm %q doesn't match the requested keySpec %q, expected %q", r.SigningAlgorithm, req.KeySpec, expectedAlg)
+	}
+
+	if len(r.CertificateChain) == 0 {
+		return NewGenericError("certificateChain cannot be empty")
+	}
+
+	return nil
+}
+
 // GenerateEnvelopeRequest contains the parameters passed in a generate-envelope
 // request.
 type GenerateEnvelopeRequest struct {
@@ -111,3 +172,21 @@ type GenerateEnvelopeResponse struct {
 	SignatureEnvelopeType string            `json:"signatureEnvelopeType"`
 	Annotations           map[string]string `json:"annotations,omitempty"`
 }
+
+// Validate validates GenerateEnvelopeResponse struct against the originating
+// GenerateEnvelopeRequest.
+func (r *GenerateEnvelopeResponse) Validate(req *GenerateEnvelopeRequest) error {
+	if r == nil {
+		return NewGenericError("response cannot be empty")
+	}
+
+	if len(r.SignatureEnvelope) == 0 {
+		return NewGenericError("signatureEnvelope cannot be empty")
+	}
+
+	if r.SignatureEnvelopeType != req.SignatureEnvelopeType {
+		return NewGenericErrorf("signatureEnvelopeType %q doesn't match the requested signatureEnvelopeType %q", r.SignatureEnvelopeType, req.SignatureEnvelopeType)
+	}
+
+	return nil
+}
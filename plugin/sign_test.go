@@ -14,6 +14,8 @@
 package plugin
 
 import (
+	"bytes"
+	"crypto"
 	"fmt"
 	"testing"
 )
@@ -66,6 +68,43 @@ func TestGenerateSignatureRequest_Validate_Error(t *testing.T) {
 	}
 }
 
+func TestGenerateSignatureRequest_Validate_Unsupported(t *testing.T) {
+	testCases := []struct {
+		name   string
+		req    GenerateSignatureRequest
+		expMsg string
+	}{
+		{
+			name:   "keySpec",
+			req:    getGenerateSignatureRequest(ContractVersion, "someKeyId", "RSA-1024", string(HashAlgorithmSHA256), []byte("zop")),
+			expMsg: "keySpec \\\"RSA-1024\\\" is not supported",
+		},
+		{
+			name:   "hashAlgorithm",
+			req:    getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC256), "MD5", []byte("zop")),
+			expMsg: "hashAlgorithm \\\"MD5\\\" is not supported",
+		},
+		{
+			name:   "mismatch",
+			req:    getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC256), string(HashAlgorithmSHA512), []byte("zop")),
+			expMsg: "hashAlgorithm \\\"SHA-512\\\" doesn't match the keySpec \\\"EC-256\\\", expected \\\"SHA-256\\\"",
+		},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			if err := testcase.req.Validate(); err != nil {
+				expMsg := fmt.Sprintf("{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"%s\"}", testcase.expMsg)
+				if err.Error() != expMsg {
+					t.Errorf("expected error message '%s' but got '%s'", expMsg, err.Error())
+				}
+			} else {
+				t.Error("GenerateSignatureRequest#Validate didn't returned error")
+			}
+		})
+	}
+}
+
 func TestGenerateSignatureRequest_Command(t *testing.T) {
 	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(HashAlgorithmSHA384), []byte("zop"))
 	if cmd := req.Command(); cmd != CommandGenerateSignature {
@@ -73,6 +112,40 @@ func TestGenerateSignatureRequest_Command(t *testing.T) {
 	}
 }
 
+func TestGenerateSignatureRequest_Digest(t *testing.T) {
+	testCases := []struct {
+		hashAlg HashAlgorithm
+		hash    crypto.Hash
+	}{
+		{HashAlgorithmSHA256, crypto.SHA256},
+		{HashAlgorithmSHA384, crypto.SHA384},
+		{HashAlgorithmSHA512, crypto.SHA512},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(string(testcase.hashAlg), func(t *testing.T) {
+			req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(testcase.hashAlg), []byte("zop"))
+			digest, hash, err := req.Digest()
+			if err != nil {
+				t.Fatalf("GenerateSignatureRequest#Digest failed with error: %+v", err)
+			}
+			if hash != testcase.hash {
+				t.Errorf("expected crypto hash %v but got %v", testcase.hash, hash)
+			}
+			h := testcase.hash.New()
+			h.Write([]byte("zop"))
+			if expected := h.Sum(nil); !bytes.Equal(digest, expected) {
+				t.Errorf("expected digest %x but got %x", expected, digest)
+			}
+		})
+	}
+
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), "MD5", []byte("zop"))
+	if _, _, err := req.Digest(); err == nil {
+		t.Error("GenerateSignatureRequest#Digest didn't returned error for unsupported hashAlgorithm")
+	}
+}
+
 func TestGenerateEnvelopeRequest_Validate(t *testing.T) {
 	reqs := []GenerateEnvelopeRequest{
 		getGenerateEnvelopeRequest(ContractVersion, "someKeyId", "someSET", "somePT", []byte("zop")),
@@ -128,6 +201,74 @@ func TestGenerateEnvelopeRequest_Command(t *testing.T) {
 	}
 }
 
+func TestGenerateSignatureResponse_Validate(t *testing.T) {
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(HashAlgorithmSHA384), []byte("zop"))
+	resp := getGenerateSignatureResponse("someKeyId", []byte("sig"), SignatureAlgorithmECDSA_SHA384, [][]byte{[]byte("cert")})
+	if err := resp.Validate(&req); err != nil {
+		t.Errorf("GenerateSignatureResponse#Validate failed with error: %+v", err)
+	}
+}
+
+func TestGenerateSignatureResponse_Validate_Error(t *testing.T) {
+	req := getGenerateSignatureRequest(ContractVersion, "someKeyId", string(KeySpecEC384), string(HashAlgorithmSHA384), []byte("zop"))
+	unknownKeySpecReq := getGenerateSignatureRequest(ContractVersion, "someKeyId", "RSA-1024", string(HashAlgorithmSHA384), []byte("zop"))
+	certChain := [][]byte{[]byte("cert")}
+	testCases := []struct {
+		name string
+		req  GenerateSignatureRequest
+		resp *GenerateSignatureResponse
+		msg  string
+	}{
+		{name: "nil", req: req, resp: nil, msg: "response cannot be empty"},
+		{name: "keyId", req: req, resp: getGenerateSignatureResponse("otherKeyId", []byte("sig"), SignatureAlgorithmECDSA_SHA384, certChain), msg: "keyId \\\"otherKeyId\\\" doesn't match the requested keyId \\\"someKeyId\\\""},
+		{name: "signature", req: req, resp: getGenerateSignatureResponse("someKeyId", nil, SignatureAlgorithmECDSA_SHA384, certChain), msg: "signature cannot be empty"},
+		{name: "signingAlgorithm", req: req, resp: getGenerateSignatureResponse("someKeyId", []byte("sig"), SignatureAlgorithmRSASSA_PSS_SHA384, certChain), msg: "signingAlgorithm \\\"RSASSA-PSS-SHA-384\\\" doesn't match the requested keySpec \\\"EC-384\\\", expected \\\"ECDSA-SHA-384\\\""},
+		{name: "keySpec", req: unknownKeySpecReq, resp: getGenerateSignatureResponse("someKeyId", []byte("sig"), SignatureAlgorithmRSASSA_PSS_SHA256, certChain), msg: "keySpec \\\"RSA-1024\\\" is not supported"},
+		{name: "certificateChain", req: req, resp: getGenerateSignatureResponse("someKeyId", []byte("sig"), SignatureAlgorithmECDSA_SHA384, [][]byte{}), msg: "certificateChain cannot be empty"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate(&testcase.req)
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s\"}", testcase.msg)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
+
+func TestGenerateEnvelopeResponse_Validate(t *testing.T) {
+	req := getGenerateEnvelopeRequest(ContractVersion, "someKeyId", "someSET", "somePT", []byte("zop"))
+	resp := &GenerateEnvelopeResponse{SignatureEnvelope: []byte("envelope"), SignatureEnvelopeType: "someSET"}
+	if err := resp.Validate(&req); err != nil {
+		t.Errorf("GenerateEnvelopeResponse#Validate failed with error: %+v", err)
+	}
+}
+
+func TestGenerateEnvelopeResponse_Validate_Error(t *testing.T) {
+	req := getGenerateEnvelopeRequest(ContractVersion, "someKeyId", "someSET", "somePT", []byte("zop"))
+	testCases := []struct {
+		name string
+		resp *GenerateEnvelopeResponse
+		msg  string
+	}{
+		{name: "nil", resp: nil, msg: "response cannot be empty"},
+		{name: "signatureEnvelope", resp: &GenerateEnvelopeResponse{SignatureEnvelopeType: "someSET"}, msg: "signatureEnvelope cannot be empty"},
+		{name: "signatureEnvelopeType", resp: &GenerateEnvelopeResponse{SignatureEnvelope: []byte("envelope"), SignatureEnvelopeType: "otherSET"}, msg: "signatureEnvelopeType \\\"otherSET\\\" doesn't match the requested signatureEnvelopeType \\\"someSET\\\""},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate(&req)
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s\"}", testcase.msg)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
+
 func getGenerateSignatureRequest(cv, kid, ks, ha string, pl []byte) GenerateSignatureRequest {
 	return GenerateSignatureRequest{
 		ContractVersion: cv,
@@ -147,3 +288,12 @@ func getGenerateEnvelopeRequest(cv, kid, set, pt string, pl []byte) GenerateEnve
 		Payload:               pl,
 	}
 }
+
+func getGenerateSignatureResponse(kid string, sig []byte, alg SignatureAlgorithm, cc [][]byte) *GenerateSignatureResponse {
+	return &GenerateSignatureResponse{
+		KeyID:            kid,
+		Signature:        sig,
+		SigningAlgorithm: alg,
+		CertificateChain: cc,
+	}
+}
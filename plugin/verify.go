@@ -16,6 +16,8 @@ package plugin
 import (
 	"reflect"
 	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
 )
 
 // VerifySignatureRequest contains the parameters passed in a verify-signature
@@ -65,6 +67,15 @@ func (r VerifySignatureRequest) Validate() error {
 		return NewValidationError("signature's trustPolicy's signatureVerification cannot be empty")
 	}
 
+	for _, capability := range r.TrustPolicy.SignatureVerification {
+		if err := capability.Validate(); err != nil {
+			return err
+		}
+		if !slices.Contains(verifierCapabilities, capability) {
+			return NewValidationErrorf("signature's trustPolicy's signatureVerification contains %q, which is not a verifier capability", capability)
+		}
+	}
+
 	return nil
 }
 
@@ -97,6 +108,33 @@ type VerifySignatureResponse struct {
 	ProcessedAttributes []interface{}                      `json:"processedAttributes"`
 }
 
+// Validate validates VerifySignatureResponse struct against the originating
+// VerifySignatureRequest. The verification results must contain exactly the
+// capabilities requested by the trust policy.
+func (r *VerifySignatureResponse) Validate(req *VerifySignatureRequest) error {
+	if r == nil {
+		return NewGenericError("response cannot be empty")
+	}
+
+	requested := req.TrustPolicy.SignatureVerification
+	for capability, result := range r.VerificationResults {
+		if !slices.Contains(requested, capability) {
+			return NewGenericErrorf("verificationResults contains result for capability %q which was not requested", capability)
+		}
+		if result == nil {
+			return NewGenericErrorf("verificationResults's result for capability %q cannot be empty", capability)
+		}
+	}
+
+	for _, capability := range requested {
+		if _, ok := r.VerificationResults[capability]; !ok {
+			return NewGenericErrorf("verificationResults doesn't contain result for requested capability %q", capability)
+		}
+	}
+
+	return nil
+}
+
 // VerificationResult is the result of a verification performed by the plugin
 type VerificationResult struct {
 	Success bool   `json:"success"`
@@ -22,7 +22,7 @@ var mockCertChain = [][]byte{[]byte("zap"), []byte("zop")}
 
 func TestVerifySignatureRequest_Validate(t *testing.T) {
 	reqs := []VerifySignatureRequest{
-		getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator}),
+		getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier}),
 		{
 			ContractVersion: "2.0",
 			Signature: Signature{
@@ -38,7 +38,7 @@ func TestVerifySignatureRequest_Validate(t *testing.T) {
 			},
 			TrustPolicy: TrustPolicy{
 				TrustedIdentities:     []string{"trustedIdentity1", "trustedIdentity2"},
-				SignatureVerification: []Capability{CapabilitySignatureGenerator, CapabilityRevocationCheckVerifier},
+				SignatureVerification: []Capability{CapabilityTrustedIdentityVerifier, CapabilityRevocationCheckVerifier},
 			},
 			PluginConfig: map[string]string{"someKey": "someValue"},
 		},
@@ -52,21 +52,21 @@ func TestVerifySignatureRequest_Validate(t *testing.T) {
 }
 
 func TestVerifySignatureRequest_Validate_Error(t *testing.T) {
-	reqWithoutSignature := getVerifySignatureRequest("1.0", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator})
+	reqWithoutSignature := getVerifySignatureRequest("1.0", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})
 	reqWithoutSignature.Signature = Signature{}
 
-	reqWithoutCriticalAttr := getVerifySignatureRequest("1.0", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator})
+	reqWithoutCriticalAttr := getVerifySignatureRequest("1.0", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})
 	reqWithoutCriticalAttr.Signature.CriticalAttributes = CriticalAttributes{}
 
 	testCases := []struct {
 		name string
 		req  VerifySignatureRequest
 	}{
-		{name: "contractVersion", req: getVerifySignatureRequest("", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator})},
-		{name: "signature's criticalAttributes's contentType", req: getVerifySignatureRequest(ContractVersion, "", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator})},
-		{name: "signature's criticalAttributes's signingScheme", req: getVerifySignatureRequest(ContractVersion, "someCT", "", mockCertChain, []Capability{CapabilitySignatureGenerator})},
-		{name: "signature's criticalAttributes's certificateChain", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", [][]byte{}, []Capability{CapabilitySignatureGenerator})},
-		{name: "signature's criticalAttributes's certificateChain", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", nil, []Capability{CapabilitySignatureGenerator})},
+		{name: "contractVersion", req: getVerifySignatureRequest("", "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})},
+		{name: "signature's criticalAttributes's contentType", req: getVerifySignatureRequest(ContractVersion, "", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})},
+		{name: "signature's criticalAttributes's signingScheme", req: getVerifySignatureRequest(ContractVersion, "someCT", "", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})},
+		{name: "signature's criticalAttributes's certificateChain", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", [][]byte{}, []Capability{CapabilityTrustedIdentityVerifier})},
+		{name: "signature's criticalAttributes's certificateChain", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", nil, []Capability{CapabilityTrustedIdentityVerifier})},
 		{name: "signature's trustPolicy", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, nil)},
 		{name: "signature's trustPolicy's signatureVerification", req: getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{})},
 		{name: "signature", req: reqWithoutSignature},
@@ -87,13 +87,84 @@ func TestVerifySignatureRequest_Validate_Error(t *testing.T) {
 	}
 }
 
+func TestVerifySignatureRequest_Validate_Capability(t *testing.T) {
+	testCases := []struct {
+		name       string
+		capability Capability
+		expMsg     string
+	}{
+		{name: "unknown", capability: "SIGNATURE_VERIFIER.UNKNOWN", expMsg: "capability \\\"SIGNATURE_VERIFIER.UNKNOWN\\\" is not supported"},
+		{name: "generator", capability: CapabilitySignatureGenerator, expMsg: "signature's trustPolicy's signatureVerification contains \\\"SIGNATURE_GENERATOR.RAW\\\", which is not a verifier capability"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			req := getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier, testcase.capability})
+			if err := req.Validate(); err != nil {
+				expMsg := fmt.Sprintf("{\"errorCode\":\"VALIDATION_ERROR\",\"errorMessage\":\"%s\"}", testcase.expMsg)
+				if err.Error() != expMsg {
+					t.Errorf("expected error message '%s' but got '%s'", expMsg, err.Error())
+				}
+			} else {
+				t.Error("VerifySignatureRequest#Validate didn't returned error")
+			}
+		})
+	}
+}
+
 func TestVerifySignatureRequest_Command(t *testing.T) {
-	req := getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilitySignatureGenerator})
+	req := getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})
 	if cmd := req.Command(); cmd != CommandVerifySignature {
 		t.Errorf("DescribeKeyRequest#Command, expected %s but returned %s", CommandVerifySignature, cmd)
 	}
 }
 
+func TestVerifySignatureResponse_Validate(t *testing.T) {
+	req := getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier, CapabilityRevocationCheckVerifier})
+	resp := &VerifySignatureResponse{
+		VerificationResults: map[Capability]*VerificationResult{
+			CapabilityTrustedIdentityVerifier: {Success: true},
+			CapabilityRevocationCheckVerifier: {Success: false, Reason: "revoked"},
+		},
+	}
+	if err := resp.Validate(&req); err != nil {
+		t.Errorf("VerifySignatureResponse#Validate failed with error: %+v", err)
+	}
+}
+
+func TestVerifySignatureResponse_Validate_Error(t *testing.T) {
+	req := getVerifySignatureRequest(ContractVersion, "someCT", "someSigningScheme", mockCertChain, []Capability{CapabilityTrustedIdentityVerifier})
+	testCases := []struct {
+		name string
+		resp *VerifySignatureResponse
+		msg  string
+	}{
+		{name: "nil", resp: nil, msg: "response cannot be empty"},
+		{name: "missing", resp: &VerifySignatureResponse{}, msg: "verificationResults doesn't contain result for requested capability \\\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\\\""},
+		{name: "unrequested", resp: &VerifySignatureResponse{
+			VerificationResults: map[Capability]*VerificationResult{
+				CapabilityTrustedIdentityVerifier: {Success: true},
+				CapabilityRevocationCheckVerifier: {Success: true},
+			},
+		}, msg: "verificationResults contains result for capability \\\"SIGNATURE_VERIFIER.REVOCATION_CHECK\\\" which was not requested"},
+		{name: "nilResult", resp: &VerifySignatureResponse{
+			VerificationResults: map[Capability]*VerificationResult{
+				CapabilityTrustedIdentityVerifier: nil,
+			},
+		}, msg: "verificationResults's result for capability \\\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\\\" cannot be empty"},
+	}
+
+	for _, testcase := range testCases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := testcase.resp.Validate(&req)
+			expMsg := fmt.Sprintf("{\"errorCode\":\"ERROR\",\"errorMessage\":\"%s\"}", testcase.msg)
+			if err == nil || err.Error() != expMsg {
+				t.Errorf("expected error message '%s' but got '%v'", expMsg, err)
+			}
+		})
+	}
+}
+
 func getVerifySignatureRequest(cv, ct, ss string, cc [][]byte, sv []Capability) VerifySignatureRequest {
 	return VerifySignatureRequest{
 		ContractVersion: cv,
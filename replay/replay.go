@@ -0,0 +1,204 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay provides a harness to replay plugin invocations recorded using cli.WithRecorder against a CLI,
+// and to report the differences between the recorded and the actual responses.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+	"github.com/notaryproject/notation-plugin-framework-go/internal/fixture"
+	"github.com/notaryproject/notation-plugin-framework-go/internal/slices"
+)
+
+// Fixture is a recorded plugin invocation.
+type Fixture = fixture.Fixture
+
+// Option configures the replay of fixtures.
+type Option func(*options)
+
+type options struct {
+	ignoredFields []string
+}
+
+// WithIgnoredFields sets the names of the JSON fields, at any depth, that are ignored when comparing the recorded
+// and the actual responses or errors, e.g. "signature" for plugins generating non-deterministic signatures.
+func WithIgnoredFields(fields ...string) Option {
+	return func(o *options) {
+		o.ignoredFields = fields
+	}
+}
+
+// Result is the outcome of replaying a fixture.
+type Result struct {
+	// Fixture is the replayed fixture.
+	Fixture *Fixture
+
+	// Response is the JSON written to stdout by the CLI.
+	Response json.RawMessage
+
+	// Error is the error written to stderr by the CLI.
+	Error json.RawMessage
+
+	// ExitCode is the exit code returned by the CLI.
+	ExitCode int
+
+	// Diffs contains the differences between the recorded and the actual invocation, empty if they match.
+	Diffs []string
+}
+
+// Passed returns true if the actual invocation matches the recorded one.
+func (r *Result) Passed() bool {
+	return len(r.Diffs) == 0
+}
+
+// Load reads the fixture at given path.
+func Load(path string) (*Fixture, error) {
+	return fixture.Load(path)
+}
+
+// LoadDir reads all the fixtures in given directory, sorted by name.
+func LoadDir(dir string) ([]*Fixture, error) {
+	return fixture.LoadDir(dir)
+}
+
+// Replay feeds the request of given fixture through given CLI and compares the output with the recorded one.
+func Replay(ctx context.Context, c *cli.CLI, f *Fixture, opts ...Option) (*Result, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var stdout, stderr bytes.Buffer
+	result := &Result{Fixture: f}
+	result.ExitCode = c.Run(ctx, f.Args, bytes.NewReader(f.Request), &stdout, &stderr)
+	result.Response = bytes.TrimSpace(stdout.Bytes())
+	result.Error = bytes.TrimSpace(stderr.Bytes())
+
+	if result.ExitCode != f.ExitCode {
+		result.Diffs = append(result.Diffs, fmt.Sprintf("exit code: expected %d but got %d", f.ExitCode, result.ExitCode))
+	}
+	diff, err := compare("response", f.Response, result.Response, o.ignoredFields)
+	if err != nil {
+		return nil, err
+	}
+	if diff != "" {
+		result.Diffs = append(result.Diffs, diff)
+	}
+	if diff, err = compare("error", f.Error, result.Error, o.ignoredFields); err != nil {
+		return nil, err
+	}
+	if diff != "" {
+		result.Diffs = append(result.Diffs, diff)
+	}
+	return result, nil
+}
+
+// ReplayDir replays all the fixtures in given directory against given CLI.
+func ReplayDir(ctx context.Context, c *cli.CLI, dir string, opts ...Option) ([]*Result, error) {
+	fixtures, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, 0, len(fixtures))
+	for _, f := range fixtures {
+		result, err := Replay(ctx, c, f, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay fixture %s: %w", f.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Test replays all the fixtures in given directory against given CLI and returns an error describing the
+// differences of every fixture whose actual invocation doesn't match the recorded one, or nil if all of them match.
+// It doesn't depend on the testing package, so it is meant to be called from a test like this:
+//
+//	if err := replay.Test(ctx, c, "testdata"); err != nil {
+//		t.Fatal(err)
+//	}
+func Test(ctx context.Context, c *cli.CLI, dir string, opts ...Option) error {
+	results, err := ReplayDir(ctx, c, dir, opts...)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no fixtures found in %s", dir)
+	}
+
+	var diffs []string
+	for _, result := range results {
+		for _, diff := range result.Diffs {
+			diffs = append(diffs, fmt.Sprintf("fixture %s: %s", result.Fixture.Name, diff))
+		}
+	}
+	if len(diffs) != 0 {
+		return errors.New(strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// compare returns a description of the difference between the expected and the actual JSON values, ignoring given
+// fields, or an empty string if they are equal.
+func compare(name string, expected, actual json.RawMessage, ignoredFields []string) (string, error) {
+	var exp, act any
+	if len(expected) != 0 {
+		if err := json.Unmarshal(expected, &exp); err != nil {
+			return "", fmt.Errorf("recorded %s is not a valid JSON: %w", name, err)
+		}
+	}
+	if len(actual) != 0 && json.Unmarshal(actual, &act) != nil {
+		return fmt.Sprintf("%s: expected %s but got %q", name, expected, actual), nil
+	}
+
+	if reflect.DeepEqual(removeFields(exp, ignoredFields), removeFields(act, ignoredFields)) {
+		return "", nil
+	}
+	return fmt.Sprintf("%s: expected %s but got %s", name, orNone(expected), orNone(actual)), nil
+}
+
+// removeFields removes given fields from v, at any depth.
+func removeFields(v any, fields []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if slices.Contains(fields, k) {
+				delete(t, k)
+			} else {
+				t[k] = removeFields(val, fields)
+			}
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = removeFields(val, fields)
+		}
+	}
+	return v
+}
+
+func orNone(data json.RawMessage) string {
+	if len(data) == 0 {
+		return "none"
+	}
+	return string(data)
+}
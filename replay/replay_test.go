@@ -0,0 +1,150 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+	"github.com/notaryproject/notation-plugin-framework-go/internal/mock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+func TestReplayTestdata(t *testing.T) {
+	c, err := cli.New(mock.NewSigGeneratorPlugin(false))
+	if err != nil {
+		t.Fatalf("cli.New() failed with error: %v", err)
+	}
+	if err := Test(context.Background(), c, "testdata"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	c, err := cli.New(mock.NewSigGeneratorPlugin(false), cli.WithRecorder(dir))
+	if err != nil {
+		t.Fatalf("cli.New() failed with error: %v", err)
+	}
+	args := []string{"/plugins/com.example.plugin/notation-com.example.plugin", "describe-key"}
+	var stdout, stderr bytes.Buffer
+	if code := c.Run(context.Background(), args, strings.NewReader("{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\"}"), &stdout, &stderr); code != 0 {
+		t.Fatalf("Run() expected exit code 0 but got %d, stderr: %s", code, stderr.String())
+	}
+
+	results, err := ReplayDir(context.Background(), c, dir)
+	if err != nil {
+		t.Fatalf("ReplayDir() failed with error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("ReplayDir() expected 1 result but found %d", len(results))
+	}
+	if !results[0].Passed() {
+		t.Errorf("ReplayDir() expected result to pass but found diffs %v", results[0].Diffs)
+	}
+	if expected := []string{"notation-com.example.plugin", "describe-key"}; !reflect.DeepEqual(results[0].Fixture.Args, expected) {
+		t.Errorf("expected recorded args %v but found %v", expected, results[0].Fixture.Args)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	c, err := cli.New(mock.NewSigGeneratorPlugin(false))
+	if err != nil {
+		t.Fatalf("cli.New() failed with error: %v", err)
+	}
+	f, err := Load(filepath.Join("testdata", "generate-signature.json"))
+	if err != nil {
+		t.Fatalf("Load() failed with error: %v", err)
+	}
+	f.Response = json.RawMessage("{\"keyId\":\"someKeyId\",\"signature\":\"b3RoZXI=\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]}")
+
+	result, err := Replay(context.Background(), c, f)
+	if err != nil {
+		t.Fatalf("Replay() failed with error: %v", err)
+	}
+	expected := []string{"response: expected {\"keyId\":\"someKeyId\",\"signature\":\"b3RoZXI=\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]} but got {\"keyId\":\"someKeyId\",\"signature\":\"YWJjZA==\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-256\",\"certificateChain\":[\"YWJjZA==\",\"d3h5eg==\"]}"}
+	if result.Passed() || !reflect.DeepEqual(result.Diffs, expected) {
+		t.Errorf("Replay() expected diffs %v but found %v", expected, result.Diffs)
+	}
+
+	result, err = Replay(context.Background(), c, f, WithIgnoredFields("signature"))
+	if err != nil {
+		t.Fatalf("Replay() failed with error: %v", err)
+	}
+	if !result.Passed() {
+		t.Errorf("Replay() expected result to pass with ignored signature but found diffs %v", result.Diffs)
+	}
+}
+
+func TestReplayErrorMismatch(t *testing.T) {
+	c, err := cli.New(mock.NewSigGeneratorPlugin(false), cli.WithInterceptors(func(_ context.Context, _ plugin.Command, _ plugin.Request, _ cli.Handler) (any, error) {
+		return nil, plugin.NewError(plugin.ErrorCodeAccessDenied, "access denied")
+	}))
+	if err != nil {
+		t.Fatalf("cli.New() failed with error: %v", err)
+	}
+	f, err := Load(filepath.Join("testdata", "describe-key.json"))
+	if err != nil {
+		t.Fatalf("Load() failed with error: %v", err)
+	}
+
+	result, err := Replay(context.Background(), c, f)
+	if err != nil {
+		t.Fatalf("Replay() failed with error: %v", err)
+	}
+	if len(result.Diffs) != 3 {
+		t.Errorf("Replay() expected exit code, response and error diffs but found %v", result.Diffs)
+	}
+
+	err = Test(context.Background(), c, "testdata")
+	if err == nil || !strings.Contains(err.Error(), "fixture describe-key: exit code: expected 0 but got 3") {
+		t.Errorf("Test() expected describe-key exit code diff but found %v", err)
+	}
+}
+
+func TestTestEmptyDir(t *testing.T) {
+	c, err := cli.New(mock.NewSigGeneratorPlugin(false))
+	if err != nil {
+		t.Fatalf("cli.New() failed with error: %v", err)
+	}
+	if err := Test(context.Background(), c, t.TempDir()); err == nil {
+		t.Error("Test() expected error for directory without fixtures")
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"invalid.json":    "{",
+		"incomplete.json": "{\"command\":\"describe-key\"}",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Errorf("Load() expected error for %s but not found", name)
+			}
+		})
+	}
+}
@@ -23,8 +23,8 @@ import (
 	"testing"
 )
 
-var sigGenPluginPath = flag.String("sig_gen_plugin", "./bin/signaturegenerator/com.example.plugin", "dir of package containing embedded files")
-var envGenPluginPath = flag.String("env_gen_plugin", "./bin/envelopegenerator/com.example.plugin", "dir of package containing embedded files")
+var sigGenPluginPath = flag.String("sig_gen_plugin", "./bin/signaturegenerator/notation-com.example.plugin", "dir of package containing embedded files")
+var envGenPluginPath = flag.String("env_gen_plugin", "./bin/envelopegenerator/notation-com.example.plugin", "dir of package containing embedded files")
 
 func TestSuccess(t *testing.T) {
 	tests := map[string]struct {
@@ -43,7 +43,7 @@ func TestSuccess(t *testing.T) {
 			expectedStdout: "{\"name\":\"com.example.plugin\",\"description\":\"This is an description of example plugin\",\"version\":\"1.0.0\",\"url\":\"https://example.com/notation/plugin\",\"supportedContractVersions\":[\"1.0\"],\"capabilities\":[\"SIGNATURE_GENERATOR.ENVELOPE\",\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\"]}"},
 		"verify-signature": {
 			pluginPath:     envGenPluginPath,
-			stdin:          "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"someCT\",\"signingScheme\":\"someSigningScheme\"},\"unprocessedAttributes\":null,\"certificateChain\":[\"emFw\",\"em9w\"]},\"trustPolicy\":{\"trustedIdentities\":null,\"signatureVerification\":[\"SIGNATURE_GENERATOR.RAW\"]}}",
+			stdin:          "{\"contractVersion\":\"1.0\",\"signature\":{\"criticalAttributes\":{\"contentType\":\"someCT\",\"signingScheme\":\"someSigningScheme\"},\"unprocessedAttributes\":null,\"certificateChain\":[\"emFw\",\"em9w\"]},\"trustPolicy\":{\"trustedIdentities\":null,\"signatureVerification\":[\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\",\"SIGNATURE_VERIFIER.REVOCATION_CHECK\"]}}",
 			expectedStdout: "{\"verificationResults\":{\"SIGNATURE_VERIFIER.REVOCATION_CHECK\":{\"success\":true,\"reason\":\"Not revoked\"},\"SIGNATURE_VERIFIER.TRUSTED_IDENTITY\":{\"success\":true,\"reason\":\"Valid trusted Identity\"}},\"processedAttributes\":[]}"},
 		"version": {
 			pluginPath:     envGenPluginPath,
@@ -52,7 +52,7 @@ func TestSuccess(t *testing.T) {
 		},
 		"generate-signature": {
 			pluginPath:     sigGenPluginPath,
-			stdin:          "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"EC-384\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}",
+			stdin:          "{\"contractVersion\":\"1.0\",\"keyId\":\"someKeyId\",\"keySpec\":\"RSA-3072\",\"hashAlgorithm\":\"SHA-384\",\"payload\":\"em9w\"}",
 			expectedStdout: "{\"keyId\":\"someKeyId\",\"signature\":\"Z2VuZXJhdGVkTW9ja1NpZ25hdHVyZQ==\",\"signingAlgorithm\":\"RSASSA-PSS-SHA-384\",\"certificateChain\":[\"bW9ja0NlcnQx\",\"bW9ja0NlcnQy\"]}",
 		},
 		"describe-key": {
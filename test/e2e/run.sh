@@ -17,7 +17,7 @@
 echo "building example plugins..."
 echo "=============================="
 CWD=$(pwd)
-PLUGIN_NAME=com.example.plugin
+PLUGIN_NAME=notation-com.example.plugin
 plugin_directories=( envelopegenerator signaturegenerator )
 for plugin_directory in "${plugin_directories[@]}"
 do